@@ -0,0 +1,213 @@
+package sakuracloud
+
+import (
+	"fmt"
+	"github.com/docker/go-units"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/sacloud/libsacloud/api"
+	"github.com/sacloud/libsacloud/sacloud"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const diskRestoreFromLatestBackup = "latest"
+
+func dataSourceSakuraCloudAutoBackupArchives() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceSakuraCloudAutoBackupArchivesRead,
+
+		Schema: map[string]*schema.Schema{
+			"disk_id": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Computed:      true,
+				ValidateFunc:  validateSakuracloudIDType,
+				ConflictsWith: []string{"auto_backup_id"},
+			},
+			"auto_backup_id": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ValidateFunc:  validateSakuracloudIDType,
+				ConflictsWith: []string{"disk_id"},
+			},
+			"ids": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"archives": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"generation": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"size": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"created_at": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+			"zone": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ForceNew:     true,
+				Description:  "target SakuraCloud zone",
+				ValidateFunc: validateStringInWord([]string{"is1b", "tk1a"}),
+			},
+		},
+	}
+}
+
+func dataSourceSakuraCloudAutoBackupArchivesRead(d *schema.ResourceData, meta interface{}) error {
+	c := meta.(*api.Client)
+	client := c.Clone()
+	zone, ok := d.GetOk("zone")
+	if ok {
+		client.Zone = zone.(string)
+	}
+
+	var diskID int64
+	autoBackupID := ""
+	if rawID, ok := d.GetOk("auto_backup_id"); ok {
+		autoBackup, err := client.AutoBackup.Read(toSakuraCloudID(rawID.(string)))
+		if err != nil {
+			return fmt.Errorf("Couldn't find SakuraCloud AutoBackup resource: %s", err)
+		}
+		diskID = toSakuraCloudID(autoBackup.Status.DiskID)
+		autoBackupID = autoBackup.GetStrID()
+	} else if rawID, ok := d.GetOk("disk_id"); ok {
+		diskID = toSakuraCloudID(rawID.(string))
+	} else {
+		return fmt.Errorf("Either disk_id or auto_backup_id is required")
+	}
+
+	archives, err := findAutoBackupArchives(client, diskID, autoBackupID)
+	if err != nil {
+		return err
+	}
+
+	ids := []string{}
+	values := []interface{}{}
+	for i, archive := range archives {
+		createdAt := ""
+		if archive.CreatedAt != nil {
+			createdAt = archive.CreatedAt.Format(time.RFC3339)
+		}
+		ids = append(ids, archive.GetStrID())
+		values = append(values, map[string]interface{}{
+			"id":         archive.GetStrID(),
+			"name":       archive.Name,
+			"generation": i,
+			"size":       archive.SizeMB * units.MiB / units.GiB,
+			"created_at": createdAt,
+		})
+	}
+
+	d.SetId(fmt.Sprintf("%d", diskID))
+	d.Set("disk_id", fmt.Sprintf("%d", diskID))
+	d.Set("ids", ids)
+	d.Set("archives", values)
+	d.Set("zone", client.Zone)
+
+	return nil
+}
+
+// autoBackupArchiveTagPrefix is the prefix of the tag attached to archives created by auto backup.
+// The tag is followed by the ID of the auto backup, e.g. "autobackup-123456789012".
+const autoBackupArchiveTagPrefix = "autobackup-"
+
+// autoBackupArchivesPageSize is the number of archives fetched by one request
+const autoBackupArchivesPageSize = 100
+
+// findAutoBackupArchives returns the archives created by auto backup of the disk, newest first.
+// If autoBackupID is empty, archives created by any auto backup of the disk are returned.
+// The index of the returned slice is the generation of the backup.
+func findAutoBackupArchives(client *api.Client, diskID int64, autoBackupID string) ([]sacloud.Archive, error) {
+	archives := []sacloud.Archive{}
+	for offset := 0; ; offset += autoBackupArchivesPageSize {
+		res, err := client.Archive.Reset().WithUserScope().Offset(offset).Limit(autoBackupArchivesPageSize).Find()
+		if err != nil {
+			return nil, fmt.Errorf("Couldn't find SakuraCloud Archive resource: %s", err)
+		}
+		for _, archive := range res.Archives {
+			if archive.SourceDisk != nil && archive.SourceDisk.ID == diskID && isAutoBackupArchive(&archive, autoBackupID) {
+				archives = append(archives, archive)
+			}
+		}
+		if len(res.Archives) < autoBackupArchivesPageSize || offset+len(res.Archives) >= res.Total {
+			break
+		}
+	}
+
+	sortAutoBackupArchives(archives)
+	return archives, nil
+}
+
+// isAutoBackupArchive returns true if the archive was created by the auto backup.
+// Archives created manually from the same disk don't have the tag.
+func isAutoBackupArchive(archive *sacloud.Archive, autoBackupID string) bool {
+	for _, tag := range archive.Tags {
+		if autoBackupID == "" && strings.HasPrefix(tag, autoBackupArchiveTagPrefix) {
+			return true
+		}
+		if autoBackupID != "" && tag == autoBackupArchiveTagPrefix+autoBackupID {
+			return true
+		}
+	}
+	return false
+}
+
+// sortAutoBackupArchives sorts archives newest first
+func sortAutoBackupArchives(archives []sacloud.Archive) {
+	sort.SliceStable(archives, func(i, j int) bool {
+		if archives[i].CreatedAt == nil || archives[j].CreatedAt == nil {
+			return archives[j].CreatedAt == nil
+		}
+		return archives[i].CreatedAt.After(*archives[j].CreatedAt)
+	})
+}
+
+// findAutoBackupArchive resolves the generation("latest" or the index) to the archive created by auto backup of the disk.
+func findAutoBackupArchive(client *api.Client, diskID int64, generation string) (*sacloud.Archive, error) {
+	archives, err := findAutoBackupArchives(client, diskID, "")
+	if err != nil {
+		return nil, err
+	}
+	return selectAutoBackupArchive(archives, diskID, generation)
+}
+
+// selectAutoBackupArchive returns the archive of the generation from archives sorted newest first
+func selectAutoBackupArchive(archives []sacloud.Archive, diskID int64, generation string) (*sacloud.Archive, error) {
+	index := 0
+	if generation != diskRestoreFromLatestBackup {
+		i, err := strconv.Atoi(generation)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid backup generation %q: %s", generation, err)
+		}
+		index = i
+	}
+
+	if index >= len(archives) {
+		return nil, fmt.Errorf("Couldn't find backup generation %q of SakuraCloud Disk(id:%d): %d archives found", generation, diskID, len(archives))
+	}
+	return &archives[index], nil
+}
@@ -0,0 +1,128 @@
+package sakuracloud
+
+import (
+	"errors"
+	"fmt"
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/sacloud/libsacloud/sacloud"
+	"testing"
+	"time"
+)
+
+func TestSakuraCloudAutoBackupArchivesSelect(t *testing.T) {
+	now := time.Now()
+	older := now.Add(-24 * time.Hour)
+	newArchive := func(id int64, createdAt *time.Time, tags ...string) sacloud.Archive {
+		archive := sacloud.Archive{Resource: sacloud.NewResource(id)}
+		archive.Tags = tags
+		archive.CreatedAt = createdAt
+		return archive
+	}
+
+	all := []sacloud.Archive{
+		newArchive(1, &older, "autobackup-100"),
+		newArchive(2, &now, "manual"),
+		newArchive(3, &now, "autobackup-100"),
+		newArchive(4, &now, "autobackup-200"),
+	}
+
+	var archives []sacloud.Archive
+	for i := range all {
+		if isAutoBackupArchive(&all[i], "100") {
+			archives = append(archives, all[i])
+		}
+	}
+	sortAutoBackupArchives(archives)
+	if len(archives) != 2 || archives[0].ID != 3 || archives[1].ID != 1 {
+		t.Fatalf("unexpected archives: %#v", archives)
+	}
+	if !isAutoBackupArchive(&all[3], "") || isAutoBackupArchive(&all[1], "") {
+		t.Errorf("archives without the auto backup tag must be excluded")
+	}
+
+	cases := map[string]struct {
+		generation string
+		id         int64
+		err        bool
+	}{
+		"latest":      {generation: "latest", id: 3},
+		"generation0": {generation: "0", id: 3},
+		"generation1": {generation: "1", id: 1},
+		"not_found":   {generation: "2", err: true},
+		"invalid":     {generation: "foo", err: true},
+	}
+	for name, c := range cases {
+		archive, err := selectAutoBackupArchive(archives, 1, c.generation)
+		if c.err {
+			if err == nil {
+				t.Errorf("%s: expected error, got %#v", name, archive)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", name, err)
+			continue
+		}
+		if archive.ID != c.id {
+			t.Errorf("%s: expected archive %d, got %d", name, c.id, archive.ID)
+		}
+	}
+}
+
+func TestAccSakuraCloudAutoBackupArchivesDataSource_Basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckSakuraCloudAutoBackupDestroy,
+
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckSakuraCloudDataSourceAutoBackupArchivesConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckSakuraCloudAutoBackupArchivesDataSourceID("data.sakuracloud_auto_backup_archives.by_disk"),
+					testAccCheckSakuraCloudAutoBackupArchivesDataSourceID("data.sakuracloud_auto_backup_archives.by_auto_backup"),
+					resource.TestCheckResourceAttrPair(
+						"data.sakuracloud_auto_backup_archives.by_auto_backup", "disk_id",
+						"sakuracloud_disk.disk", "id"),
+					resource.TestCheckResourceAttr("data.sakuracloud_auto_backup_archives.by_disk", "ids.#", "0"),
+					resource.TestCheckResourceAttr("data.sakuracloud_auto_backup_archives.by_auto_backup", "archives.#", "0"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckSakuraCloudAutoBackupArchivesDataSourceID(n string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Can't find AutoBackupArchives data source: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return errors.New("AutoBackupArchives data source ID not set")
+		}
+		return nil
+	}
+}
+
+var testAccCheckSakuraCloudDataSourceAutoBackupArchivesConfig = `
+resource "sakuracloud_disk" "disk" {
+    name = "disk01"
+    zone = "is1b"
+}
+resource "sakuracloud_auto_backup" "foobar" {
+    name = "name_before"
+    disk_id = "${sakuracloud_disk.disk.id}"
+    weekdays = ["wed","thu"]
+    zone = "is1b"
+}
+data "sakuracloud_auto_backup_archives" "by_disk" {
+    disk_id = "${sakuracloud_disk.disk.id}"
+    zone = "is1b"
+}
+data "sakuracloud_auto_backup_archives" "by_auto_backup" {
+    auto_backup_id = "${sakuracloud_auto_backup.foobar.id}"
+    zone = "is1b"
+}`
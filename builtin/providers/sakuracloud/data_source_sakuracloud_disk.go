@@ -50,6 +50,14 @@ func dataSourceSakuraCloudDisk() *schema.Resource {
 				Type:     schema.TypeString,
 				Computed: true, //ReadOnly
 			},
+			"storage_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"storage_class": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
 
 			"description": {
 				Type:     schema.TypeString,
@@ -39,7 +39,7 @@ func dataSourceSakuraCloudGSLB() *schema.Resource {
 				Computed: true,
 			},
 			"health_check": {
-				Type:     schema.TypeSet,
+				Type:     schema.TypeList,
 				Computed: true,
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
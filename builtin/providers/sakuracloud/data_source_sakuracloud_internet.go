@@ -97,6 +97,14 @@ func dataSourceSakuraCloudInternet() *schema.Resource {
 				Type:     schema.TypeString,
 				Computed: true,
 			},
+			"ipv6_prefix_tail": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"ipv6_table_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
 			"zone": {
 				Type:         schema.TypeString,
 				Optional:     true,
@@ -38,6 +38,10 @@ func dataSourceSakuraCloudNote() *schema.Resource {
 				Type:     schema.TypeString,
 				Computed: true,
 			},
+			"class": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
 			"description": {
 				Type:     schema.TypeString,
 				Computed: true,
@@ -0,0 +1,234 @@
+package sakuracloud
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/hashicorp/terraform/helper/hashcode"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/sacloud/libsacloud/api"
+	"net"
+	"strconv"
+	"strings"
+)
+
+func dataSourceSakuraCloudPacketFilterEvaluation() *schema.Resource {
+	expressions := packetFilterExpressionsSchema()
+	expressions.ConflictsWith = []string{"packet_filter_id", "vpc_router_id"}
+
+	return &schema.Resource{
+		Read: dataSourceSakuraCloudPacketFilterEvaluationRead,
+
+		Schema: map[string]*schema.Schema{
+			"packet_filter_id": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ValidateFunc:  validateSakuracloudIDType,
+				ConflictsWith: []string{"expressions", "vpc_router_id"},
+			},
+			"expressions": expressions,
+			"vpc_router_id": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ValidateFunc:  validateSakuracloudIDType,
+				ConflictsWith: []string{"packet_filter_id", "expressions"},
+			},
+			"vpc_router_direction": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "receive",
+				ValidateFunc: validateStringInWord([]string{"send", "receive"}),
+			},
+			"default_allow": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+			"flow": {
+				Type:     schema.TypeList,
+				Required: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"protocol": {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validateStringInWord([]string{"tcp", "udp", "icmp", "fragment"}),
+						},
+						"source_ip": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"source_port": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							ValidateFunc: validatePacketFilterFlowPort,
+						},
+						"dest_ip": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"dest_port": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							ValidateFunc: validatePacketFilterFlowPort,
+						},
+						"expect": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Default:      "allow",
+							ValidateFunc: validateStringInWord([]string{"allow", "deny"}),
+						},
+					},
+				},
+			},
+			"results": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"allowed": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+						"matched_rule": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+					},
+				},
+			},
+			"zone": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ForceNew:     true,
+				Description:  "target SakuraCloud zone",
+				ValidateFunc: validateStringInWord([]string{"is1a", "is1b", "tk1a", "tk1v"}),
+			},
+		},
+	}
+}
+
+func dataSourceSakuraCloudPacketFilterEvaluationRead(d *schema.ResourceData, meta interface{}) error {
+	c := meta.(*api.Client)
+	client := c.Clone()
+	zone, ok := d.GetOk("zone")
+	if ok {
+		client.Zone = zone.(string)
+	}
+
+	var rules []*filterRule
+	var source string
+	if filterID, ok := d.GetOk("packet_filter_id"); ok {
+		filter, err := client.PacketFilter.Read(toSakuraCloudID(filterID.(string)))
+		if err != nil {
+			return fmt.Errorf("Couldn't find SakuraCloud PacketFilter resource: %s", err)
+		}
+		rules = filterRulesFromPacketFilterExpressions(filter.Expression)
+		source = "packet_filter:" + filterID.(string)
+	} else if routerID, ok := d.GetOk("vpc_router_id"); ok {
+		vpcRouter, err := client.VPCRouter.Read(toSakuraCloudID(routerID.(string)))
+		if err != nil {
+			return fmt.Errorf("Couldn't find SakuraCloud VPCRouter resource: %s", err)
+		}
+		direction := d.Get("vpc_router_direction").(string)
+		if vpcRouter.Settings != nil && vpcRouter.Settings.Router != nil && vpcRouter.Settings.Router.HasFirewall() {
+			switch direction {
+			case "send":
+				rules = filterRulesFromVPCRouterFirewallRules(vpcRouter.Settings.Router.Firewall.Config[0].Send)
+			case "receive":
+				rules = filterRulesFromVPCRouterFirewallRules(vpcRouter.Settings.Router.Firewall.Config[0].Receive)
+			}
+		}
+		source = "vpc_router:" + routerID.(string) + ":" + direction
+	} else if rawExpressions, ok := d.GetOk("expressions"); ok {
+		expressions, err := expandPacketFilterExpressions(rawExpressions.([]interface{}))
+		if err != nil {
+			return err
+		}
+		rules = filterRulesFromPacketFilterExpressions(expressions)
+		source = "expressions"
+	} else {
+		// evaluating no rules lets every flow pass with default_allow, so it is rejected
+		return fmt.Errorf("One of packet_filter_id, vpc_router_id or expressions is required")
+	}
+
+	defaultAllow := d.Get("default_allow").(bool)
+	results := []interface{}{}
+	failures := []string{}
+	for i, raw := range d.Get("flow").([]interface{}) {
+		conf := raw.(map[string]interface{})
+		flow, err := expandPacketFilterFlow(conf)
+		if err != nil {
+			return fmt.Errorf("flow.%d: %s", i, err)
+		}
+
+		allowed, matched, err := evaluateFilterRules(rules, flow, defaultAllow)
+		if err != nil {
+			return fmt.Errorf("Failed to evaluate flow.%d with %s: %s", i, source, err)
+		}
+		results = append(results, map[string]interface{}{
+			"allowed":      allowed,
+			"matched_rule": matched,
+		})
+
+		expectAllow := conf["expect"].(string) == "allow"
+		if allowed != expectAllow {
+			by := "default action"
+			if matched >= 0 {
+				by = fmt.Sprintf("rule %d", matched)
+				if rules[matched].description != "" {
+					by += fmt.Sprintf("(%s)", rules[matched].description)
+				}
+			}
+			result := "denied"
+			if allowed {
+				result = "allowed"
+			}
+			failures = append(failures, fmt.Sprintf("flow.%d [%s] is expected to be %s, but %s by %s",
+				i, flow, conf["expect"], result, by))
+		}
+	}
+	if len(failures) > 0 {
+		return fmt.Errorf("Unexpected result of %s:\n%s", source, strings.Join(failures, "\n"))
+	}
+
+	d.SetId(packetFilterEvaluationIDHash(source, d.Get("flow").([]interface{})))
+	d.Set("results", results)
+	d.Set("zone", client.Zone)
+	return nil
+}
+
+func expandPacketFilterFlow(conf map[string]interface{}) (*filterFlow, error) {
+	flow := &filterFlow{
+		protocol:   conf["protocol"].(string),
+		sourcePort: -1,
+		destPort:   -1,
+	}
+
+	if v := forceString(conf["source_ip"]); v != "" {
+		if flow.sourceIP = net.ParseIP(v); flow.sourceIP == nil {
+			return nil, fmt.Errorf("invalid source_ip: %q", v)
+		}
+	}
+	if v := forceString(conf["dest_ip"]); v != "" {
+		if flow.destIP = net.ParseIP(v); flow.destIP == nil {
+			return nil, fmt.Errorf("invalid dest_ip: %q", v)
+		}
+	}
+	if v := forceString(conf["source_port"]); v != "" {
+		flow.sourcePort, _ = strconv.Atoi(v)
+	}
+	if v := forceString(conf["dest_port"]); v != "" {
+		flow.destPort, _ = strconv.Atoi(v)
+	}
+	return flow, nil
+}
+
+func packetFilterEvaluationIDHash(source string, flows []interface{}) string {
+	var buf bytes.Buffer
+	buf.WriteString(source)
+	for _, raw := range flows {
+		buf.WriteString(fmt.Sprintf("%v", raw))
+	}
+	return fmt.Sprintf("pfeval-%d", hashcode.String(buf.String()))
+}
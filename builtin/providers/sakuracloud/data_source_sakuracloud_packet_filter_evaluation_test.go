@@ -0,0 +1,92 @@
+package sakuracloud
+
+import (
+	"github.com/hashicorp/terraform/helper/resource"
+	"regexp"
+	"testing"
+)
+
+func TestAccSakuraCloudPacketFilterEvaluationDataSource_Basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckSakuraCloudPacketFilterDestroy,
+
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckSakuraCloudDataSourcePacketFilterEvaluationConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.sakuracloud_packet_filter_evaluation.foobar", "results.#", "2"),
+					resource.TestCheckResourceAttr("data.sakuracloud_packet_filter_evaluation.foobar", "results.0.allowed", "true"),
+					resource.TestCheckResourceAttr("data.sakuracloud_packet_filter_evaluation.foobar", "results.0.matched_rule", "0"),
+					resource.TestCheckResourceAttr("data.sakuracloud_packet_filter_evaluation.foobar", "results.1.allowed", "false"),
+					resource.TestCheckResourceAttr("data.sakuracloud_packet_filter_evaluation.foobar", "results.1.matched_rule", "1"),
+				),
+			},
+			{
+				Config:      testAccCheckSakuraCloudDataSourcePacketFilterEvaluationConfig_unexpected,
+				ExpectError: regexp.MustCompile("flow.0 \\[tcp 198.51.100.1 -> \\*:22\\] is expected to be allow, but denied by rule 1"),
+			},
+			{
+				Config:      testAccCheckSakuraCloudDataSourcePacketFilterEvaluationConfig_noSource,
+				ExpectError: regexp.MustCompile("One of packet_filter_id, vpc_router_id or expressions is required"),
+			},
+		},
+	})
+}
+
+var testAccCheckSakuraCloudDataSourcePacketFilterEvaluationConfig = `
+resource "sakuracloud_packet_filter" "foobar" {
+    name = "mypacket_filter_evaluation"
+    expressions = {
+    	protocol = "tcp"
+    	source_nw = "192.0.2.0/24"
+    	dest_port = "22"
+    }
+    expressions = {
+    	protocol = "ip"
+    	allow = false
+    }
+}
+
+data "sakuracloud_packet_filter_evaluation" "foobar" {
+    packet_filter_id = "${sakuracloud_packet_filter.foobar.id}"
+    flow = {
+    	protocol = "tcp"
+    	source_ip = "192.0.2.10"
+    	dest_port = "22"
+    }
+    flow = {
+    	protocol = "tcp"
+    	source_ip = "198.51.100.1"
+    	dest_port = "22"
+    	expect = "deny"
+    }
+}`
+
+var testAccCheckSakuraCloudDataSourcePacketFilterEvaluationConfig_unexpected = `
+data "sakuracloud_packet_filter_evaluation" "foobar" {
+    expressions = {
+    	protocol = "tcp"
+    	source_nw = "192.0.2.0/24"
+    	dest_port = "22"
+    }
+    expressions = {
+    	protocol = "ip"
+    	allow = false
+    }
+    flow = {
+    	protocol = "tcp"
+    	source_ip = "198.51.100.1"
+    	dest_port = "22"
+    }
+}`
+
+var testAccCheckSakuraCloudDataSourcePacketFilterEvaluationConfig_noSource = `
+data "sakuracloud_packet_filter_evaluation" "foobar" {
+    flow = {
+    	protocol = "tcp"
+    	source_ip = "198.51.100.1"
+    	dest_port = "22"
+    }
+}`
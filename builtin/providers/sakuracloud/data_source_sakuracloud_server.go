@@ -56,6 +56,38 @@ func dataSourceSakuraCloudServer() *schema.Resource {
 				Type:     schema.TypeString,
 				Computed: true,
 			},
+			"network_interface": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"upstream": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"user_ip_address": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"packet_filter_id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"mac_address": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"ip_address": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"hostname": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
 			"cdrom_id": {
 				Type:     schema.TypeString,
 				Computed: true,
@@ -85,6 +117,10 @@ func dataSourceSakuraCloudServer() *schema.Resource {
 				Computed: true,
 				Elem:     &schema.Schema{Type: schema.TypeString},
 			},
+			"power_state": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
 			"zone": {
 				Type:         schema.TypeString,
 				Optional:     true,
@@ -98,6 +134,10 @@ func dataSourceSakuraCloudServer() *schema.Resource {
 				Computed: true,
 				Elem:     &schema.Schema{Type: schema.TypeString},
 			},
+			"hostname": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
 			"base_nw_ipaddress": {
 				Type:       schema.TypeString,
 				Computed:   true,
@@ -175,5 +215,8 @@ func dataSourceSakuraCloudServerRead(d *schema.ResourceData, meta interface{}) e
 	}
 	server := res.Servers[0]
 
-	return setServerResourceData(d, client, &server)
+	if err := setServerResourceData(d, client, &server); err != nil {
+		return err
+	}
+	return d.Set("network_interface", flattenServerNetworkInterfaces(server.Interfaces))
 }
@@ -0,0 +1,121 @@
+package sakuracloud
+
+import (
+	"encoding/base64"
+	"fmt"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/sacloud/libsacloud/api"
+	"io/ioutil"
+	"strconv"
+)
+
+func dataSourceSakuraCloudServerVNCInfo() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceSakuraCloudServerVNCInfoRead,
+
+		Schema: map[string]*schema.Schema{
+			"server_id": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validateSakuracloudIDType,
+			},
+			"snapshot_path": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"host": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"port": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"password": {
+				Type:      schema.TypeString,
+				Computed:  true,
+				Sensitive: true,
+			},
+			"width": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"height": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"zone": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ForceNew:     true,
+				Description:  "target SakuraCloud zone",
+				ValidateFunc: validateStringInWord([]string{"is1a", "is1b", "tk1a", "tk1v"}),
+			},
+		},
+	}
+}
+
+func dataSourceSakuraCloudServerVNCInfoRead(d *schema.ResourceData, meta interface{}) error {
+	c := meta.(*api.Client)
+	client := c.Clone()
+	zone, ok := d.GetOk("zone")
+	if ok {
+		client.Zone = zone.(string)
+	}
+
+	serverID := toSakuraCloudID(d.Get("server_id").(string))
+
+	server, err := client.Server.Read(serverID)
+	if err != nil {
+		return fmt.Errorf("Couldn't find SakuraCloud Server resource(id:%d): %s", serverID, err)
+	}
+	if !server.Instance.IsUp() {
+		return fmt.Errorf("SakuraCloud Server resource(id:%d) is not running", serverID)
+	}
+
+	proxy, err := client.Server.GetVNCProxy(serverID)
+	if err != nil {
+		return fmt.Errorf("Couldn't get VNC proxy of SakuraCloud Server resource(id:%d): %s", serverID, err)
+	}
+	size, err := client.Server.GetVNCSize(serverID)
+	if err != nil {
+		return fmt.Errorf("Couldn't get VNC size of SakuraCloud Server resource(id:%d): %s", serverID, err)
+	}
+
+	if path, ok := d.GetOk("snapshot_path"); ok {
+		if err := saveServerVNCSnapshot(client, serverID, path.(string)); err != nil {
+			return err
+		}
+	}
+
+	d.SetId(server.GetStrID())
+	d.Set("host", proxy.Host)
+	port, _ := strconv.Atoi(proxy.Port)
+	d.Set("port", port)
+	d.Set("password", proxy.Password)
+	d.Set("width", size.Width)
+	d.Set("height", size.Height)
+	d.Set("zone", client.Zone)
+
+	return nil
+}
+
+// saveServerVNCSnapshot writes the current console screen of the server to path as a PNG image.
+func saveServerVNCSnapshot(client *api.Client, serverID int64, path string) error {
+	res, err := client.Server.GetVNCSnapshot(serverID, client.Server.NewVNCSnapshotRequest())
+	if err != nil {
+		return fmt.Errorf("Couldn't get VNC snapshot of SakuraCloud Server resource(id:%d): %s", serverID, err)
+	}
+
+	data, err := base64.StdEncoding.DecodeString(res.Image)
+	if err != nil {
+		return fmt.Errorf("Failed to decode VNC snapshot of SakuraCloud Server resource(id:%d): %s", serverID, err)
+	}
+
+	if err := ioutil.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("Failed to save VNC snapshot to %q: %s", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package sakuracloud
+
+import (
+	"errors"
+	"fmt"
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestAccSakuraCloudServerVNCInfoDataSource_Basic(t *testing.T) {
+	snapshotPath := filepath.Join(os.TempDir(), "sakuracloud_server_vnc_info_test.png")
+	defer os.Remove(snapshotPath)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckSakuraCloudServerDestroy,
+
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccCheckSakuraCloudDataSourceServerVNCInfoConfig, snapshotPath),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckSakuraCloudServerVNCInfoDataSourceID("data.sakuracloud_server_vnc_info.foobar"),
+					resource.TestCheckResourceAttrSet("data.sakuracloud_server_vnc_info.foobar", "host"),
+					resource.TestCheckResourceAttrSet("data.sakuracloud_server_vnc_info.foobar", "port"),
+					resource.TestCheckResourceAttrSet("data.sakuracloud_server_vnc_info.foobar", "password"),
+					testAccCheckSakuraCloudServerVNCInfoSnapshotExists(snapshotPath),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckSakuraCloudServerVNCInfoDataSourceID(n string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Can't find ServerVNCInfo data source: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return errors.New("ServerVNCInfo data source ID not set")
+		}
+		return nil
+	}
+}
+
+func testAccCheckSakuraCloudServerVNCInfoSnapshotExists(path string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		if _, err := os.Stat(path); err != nil {
+			return fmt.Errorf("VNC snapshot is not saved: %s", err)
+		}
+		return nil
+	}
+}
+
+var testAccCheckSakuraCloudDataSourceServerVNCInfoConfig = `
+resource "sakuracloud_server" "foobar" {
+    name = "myserver_vnc_info"
+    nic = "shared"
+}
+
+data "sakuracloud_server_vnc_info" "foobar" {
+    server_id = "${sakuracloud_server.foobar.id}"
+    snapshot_path = "%s"
+}
+`
@@ -35,7 +35,7 @@ func dataSourceSakuraCloudSimpleMonitor() *schema.Resource {
 				Computed: true,
 			},
 			"health_check": {
-				Type:     schema.TypeSet,
+				Type:     schema.TypeList,
 				Computed: true,
 
 				Elem: &schema.Resource{
@@ -68,10 +68,15 @@ func dataSourceSakuraCloudSimpleMonitor() *schema.Resource {
 							Type:     schema.TypeString,
 							Computed: true,
 						},
-						"excepcted_data": {
+						"expected_data": {
 							Type:     schema.TypeString,
 							Computed: true,
 						},
+						"excepcted_data": {
+							Type:       schema.TypeString,
+							Computed:   true,
+							Deprecated: "Use field 'expected_data' instead",
+						},
 						"community": {
 							Type:     schema.TypeString,
 							Computed: true,
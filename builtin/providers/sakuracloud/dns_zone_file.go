@@ -0,0 +1,381 @@
+package sakuracloud
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/sacloud/libsacloud/sacloud"
+)
+
+const defaultDNSRecordTTL = 3600
+
+type zoneFileToken struct {
+	text   string
+	quoted bool
+}
+
+type zoneFileLine struct {
+	lineNo     int
+	blankOwner bool
+	tokens     []zoneFileToken
+}
+
+// parseDNSZoneFile parses RFC 1035 master file text and returns records of the zone.
+// Owner names are converted to the relative form used by DNS appliance("@", "www", ...).
+// SOA records are skipped because they are managed by DNS appliance.
+func parseDNSZoneFile(zone string, text string) ([]sacloud.DNSRecordSet, error) {
+	lines, err := splitZoneFileLines(text)
+	if err != nil {
+		return nil, err
+	}
+
+	zone = strings.TrimSuffix(zone, ".")
+	origin := zone + "."
+	ttl := defaultDNSRecordTTL
+	owner := ""
+	allowTypes := allowDNSTypes()
+
+	var records []sacloud.DNSRecordSet
+	for _, line := range lines {
+		tokens := line.tokens
+
+		// directives
+		if !line.blankOwner && strings.HasPrefix(tokens[0].text, "$") {
+			if len(tokens) < 2 {
+				return nil, fmt.Errorf("zone_file line %d: %s requires a value", line.lineNo, tokens[0].text)
+			}
+			switch strings.ToUpper(tokens[0].text) {
+			case "$ORIGIN":
+				origin = absoluteDNSName(tokens[1].text, origin)
+			case "$TTL":
+				v, err := parseZoneFileTTL(tokens[1].text)
+				if err != nil {
+					return nil, fmt.Errorf("zone_file line %d: %s", line.lineNo, err)
+				}
+				ttl = v
+			default:
+				return nil, fmt.Errorf("zone_file line %d: unsupported directive %q", line.lineNo, tokens[0].text)
+			}
+			continue
+		}
+
+		if !line.blankOwner {
+			owner = absoluteDNSName(tokens[0].text, origin)
+			tokens = tokens[1:]
+		}
+		if owner == "" {
+			return nil, fmt.Errorf("zone_file line %d: owner name is missing", line.lineNo)
+		}
+
+		// [<TTL>] [<class>] <type> <RDATA> or [<class>] [<TTL>] <type> <RDATA>
+		recordTTL := ttl
+		for len(tokens) > 0 && !tokens[0].quoted {
+			if strings.ToUpper(tokens[0].text) == "IN" {
+				tokens = tokens[1:]
+				continue
+			}
+			if v, err := parseZoneFileTTL(tokens[0].text); err == nil {
+				recordTTL = v
+				tokens = tokens[1:]
+				continue
+			}
+			break
+		}
+		if len(tokens) < 2 {
+			return nil, fmt.Errorf("zone_file line %d: record type and data are required", line.lineNo)
+		}
+
+		rtype := strings.ToUpper(tokens[0].text)
+		if rtype == "SOA" {
+			continue
+		}
+		var found bool
+		for _, t := range allowTypes {
+			if t == rtype {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("zone_file line %d: record type %q is not supported", line.lineNo, rtype)
+		}
+
+		name, err := relativeDNSName(owner, zone)
+		if err != nil {
+			return nil, fmt.Errorf("zone_file line %d: %s", line.lineNo, err)
+		}
+
+		records = append(records, sacloud.DNSRecordSet{
+			Name:  name,
+			Type:  rtype,
+			RData: joinZoneFileRData(rtype, tokens[1:], origin),
+			TTL:   recordTTL,
+		})
+	}
+
+	return records, nil
+}
+
+// exportDNSZoneFile renders records as RFC 1035 master file text.
+func exportDNSZoneFile(zone string, records []sacloud.DNSRecordSet) string {
+	var buf bytes.Buffer
+	buf.WriteString(fmt.Sprintf("$ORIGIN %s.\n", strings.TrimSuffix(zone, ".")))
+	for _, r := range records {
+		rdata := r.RData
+		if r.Type == "TXT" {
+			rdata = quoteZoneFileTXT(rdata)
+		}
+		buf.WriteString(fmt.Sprintf("%s\t%d\tIN\t%s\t%s\n", r.Name, r.TTL, r.Type, rdata))
+	}
+	return buf.String()
+}
+
+func splitZoneFileLines(text string) ([]*zoneFileLine, error) {
+	var lines []*zoneFileLine
+	var current *zoneFileLine
+	var buf bytes.Buffer
+	var inQuote, inComment, escaped bool
+	var escapedDigits string
+	parenDepth := 0
+	lineNo := 1
+
+	flushToken := func(quoted bool) {
+		if buf.Len() > 0 || quoted {
+			current.tokens = append(current.tokens, zoneFileToken{text: buf.String(), quoted: quoted})
+		}
+		buf.Reset()
+	}
+
+	atLineHead := true
+	for _, c := range text {
+		if atLineHead {
+			atLineHead = false
+			// a line starting with blank inherits the previous owner name
+			if parenDepth == 0 {
+				current = &zoneFileLine{lineNo: lineNo, blankOwner: c == ' ' || c == '\t'}
+			}
+		}
+
+		if inComment {
+			if c != '\n' {
+				continue
+			}
+			inComment = false
+		}
+
+		// "\X" is X itself and "\DDD" is the octet of decimal DDD(RFC 1035 5.1)
+		if escaped {
+			if '0' <= c && c <= '9' {
+				escapedDigits += string(c)
+				if len(escapedDigits) == 3 {
+					n, _ := strconv.Atoi(escapedDigits)
+					if n > 255 {
+						return nil, fmt.Errorf("zone_file line %d: invalid escape \\%s", lineNo, escapedDigits)
+					}
+					buf.WriteByte(byte(n))
+					escaped = false
+					escapedDigits = ""
+				}
+				continue
+			}
+			if escapedDigits != "" {
+				return nil, fmt.Errorf("zone_file line %d: invalid escape \\%s", lineNo, escapedDigits)
+			}
+			buf.WriteRune(c)
+			escaped = false
+			continue
+		}
+		if c == '\\' {
+			escaped = true
+			continue
+		}
+
+		if inQuote {
+			switch {
+			case c == '"':
+				inQuote = false
+				flushToken(true)
+			default:
+				if c == '\n' {
+					lineNo++
+				}
+				buf.WriteRune(c)
+			}
+			continue
+		}
+
+		switch c {
+		case '"':
+			flushToken(false)
+			inQuote = true
+		case ';':
+			flushToken(false)
+			inComment = true
+		case '(':
+			flushToken(false)
+			parenDepth++
+		case ')':
+			flushToken(false)
+			if parenDepth == 0 {
+				return nil, fmt.Errorf("zone_file line %d: unbalanced parentheses", lineNo)
+			}
+			parenDepth--
+		case ' ', '\t', '\r':
+			flushToken(false)
+		case '\n':
+			flushToken(false)
+			if parenDepth == 0 {
+				if len(current.tokens) > 0 {
+					lines = append(lines, current)
+				}
+				current = nil
+			}
+			lineNo++
+			atLineHead = true
+		default:
+			buf.WriteRune(c)
+		}
+	}
+
+	if escaped {
+		return nil, fmt.Errorf("zone_file line %d: incomplete escape sequence", lineNo)
+	}
+	if inQuote {
+		return nil, fmt.Errorf("zone_file line %d: unterminated quoted string", lineNo)
+	}
+	if parenDepth != 0 {
+		return nil, fmt.Errorf("zone_file line %d: unbalanced parentheses", lineNo)
+	}
+	if current != nil {
+		flushToken(false)
+		if len(current.tokens) > 0 {
+			lines = append(lines, current)
+		}
+	}
+
+	return lines, nil
+}
+
+func parseZoneFileTTL(v string) (int, error) {
+	if v == "" {
+		return 0, fmt.Errorf("TTL is empty")
+	}
+	units := map[byte]int{'s': 1, 'm': 60, 'h': 3600, 'd': 86400, 'w': 604800}
+
+	total := 0
+	num := ""
+	for i := 0; i < len(v); i++ {
+		c := v[i]
+		if '0' <= c && c <= '9' {
+			num += string(c)
+			continue
+		}
+		unit, ok := units[c|0x20]
+		if !ok || num == "" {
+			return 0, fmt.Errorf("invalid TTL %q", v)
+		}
+		n, _ := strconv.Atoi(num)
+		total += n * unit
+		num = ""
+	}
+	if num != "" {
+		n, _ := strconv.Atoi(num)
+		total += n
+	}
+	return total, nil
+}
+
+func absoluteDNSName(name string, origin string) string {
+	switch {
+	case name == "@":
+		return origin
+	case strings.HasSuffix(name, "."):
+		return name
+	default:
+		return name + "." + origin
+	}
+}
+
+func relativeDNSName(fqdn string, zone string) (string, error) {
+	lowerFQDN := strings.ToLower(fqdn)
+	lowerZone := strings.ToLower(zone) + "."
+	switch {
+	case lowerFQDN == lowerZone:
+		return "@", nil
+	case strings.HasSuffix(lowerFQDN, "."+lowerZone):
+		return fqdn[:len(fqdn)-len(lowerZone)-1], nil
+	default:
+		return "", fmt.Errorf("%q is out of zone %q", fqdn, zone)
+	}
+}
+
+// zoneFileRDataNameFields holds the positions of domain names in RDATA of each record type.
+// DNS appliance requires FQDN for them, so relative names are qualified with the origin.
+var zoneFileRDataNameFields = map[string]int{
+	"CNAME": 0,
+	"NS":    0,
+	"PTR":   0,
+	"MX":    1,
+	"SRV":   3,
+}
+
+func joinZoneFileRData(rtype string, tokens []zoneFileToken, origin string) string {
+	nameField, hasName := zoneFileRDataNameFields[rtype]
+
+	var values []string
+	for i, t := range tokens {
+		switch {
+		case rtype == "TXT":
+			values = append(values, t.text)
+		case t.quoted:
+			values = append(values, quoteZoneFileString(t.text))
+		case hasName && i == nameField:
+			values = append(values, absoluteDNSName(t.text, origin))
+		default:
+			values = append(values, t.text)
+		}
+	}
+	if rtype == "TXT" {
+		// character-strings are concatenated
+		return strings.Join(values, "")
+	}
+	return strings.Join(values, " ")
+}
+
+// quoteZoneFileTXT renders TXT data as quoted character-strings.
+// Data longer than 255 octets is split into multiple character-strings.
+func quoteZoneFileTXT(data string) string {
+	if data == "" {
+		return `""`
+	}
+	var values []string
+	for len(data) > 255 {
+		values = append(values, quoteZoneFileString(data[:255]))
+		data = data[255:]
+	}
+	values = append(values, quoteZoneFileString(data))
+	return strings.Join(values, " ")
+}
+
+// quoteZoneFileString quotes s with RFC 1035 escapes.
+// Double quotes and backslashes are escaped with a backslash, and non-printable or non-ASCII octets are written as "\DDD".
+func quoteZoneFileString(s string) string {
+	var buf bytes.Buffer
+	buf.WriteByte('"')
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '"' || c == '\\':
+			buf.WriteByte('\\')
+			buf.WriteByte(c)
+		case c < 0x20 || c > 0x7e:
+			buf.WriteString(fmt.Sprintf("\\%03d", c))
+		default:
+			buf.WriteByte(c)
+		}
+	}
+	buf.WriteByte('"')
+	return buf.String()
+}
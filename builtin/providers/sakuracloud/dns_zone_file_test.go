@@ -0,0 +1,167 @@
+package sakuracloud
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/sacloud/libsacloud/sacloud"
+)
+
+func TestSakuraCloudParseDNSZoneFile(t *testing.T) {
+	cases := map[string]struct {
+		text    string
+		records []sacloud.DNSRecordSet
+		err     bool
+	}{
+		"relative_and_absolute_owners": {
+			text: `
+@                   IN A     192.0.2.1
+www                 IN A     192.0.2.2
+mail.example.com.   IN A     192.0.2.3
+                       AAAA  2001:db8::1
+`,
+			records: []sacloud.DNSRecordSet{
+				{Name: "@", Type: "A", RData: "192.0.2.1", TTL: 3600},
+				{Name: "www", Type: "A", RData: "192.0.2.2", TTL: 3600},
+				{Name: "mail", Type: "A", RData: "192.0.2.3", TTL: 3600},
+				{Name: "mail", Type: "AAAA", RData: "2001:db8::1", TTL: 3600},
+			},
+		},
+		"origin_and_ttl": {
+			text: `
+$TTL 1h
+$ORIGIN sub.example.com.
+host          A      192.0.2.1
+host2  300 IN A      192.0.2.2 ; comment
+$ORIGIN example.com.
+@      IN 1d MX     10 mail.example.com.
+`,
+			records: []sacloud.DNSRecordSet{
+				{Name: "host.sub", Type: "A", RData: "192.0.2.1", TTL: 3600},
+				{Name: "host2.sub", Type: "A", RData: "192.0.2.2", TTL: 300},
+				{Name: "@", Type: "MX", RData: "10 mail.example.com.", TTL: 86400},
+			},
+		},
+		"parentheses": {
+			text: `
+@  IN SOA ns1.example.com. root.example.com. (
+          2017010101 ; serial
+          3600 900 604800 300 )
+_sip._tcp IN SRV ( 10 20
+                   5060 sip.example.com. )
+`,
+			records: []sacloud.DNSRecordSet{
+				{Name: "_sip._tcp", Type: "SRV", RData: "10 20 5060 sip.example.com.", TTL: 3600},
+			},
+		},
+		"multi_string_txt": {
+			text: `@ IN TXT "v=spf1 " "include:example.net ~all"
+txt IN TXT ( "first"
+             "second" )`,
+			records: []sacloud.DNSRecordSet{
+				{Name: "@", Type: "TXT", RData: "v=spf1 include:example.net ~all", TTL: 3600},
+				{Name: "txt", Type: "TXT", RData: "firstsecond", TTL: 3600},
+			},
+		},
+		"escaped_txt": {
+			text: `@ IN TXT "quote\" backslash\\ tab\009 \195\169 raw é"`,
+			records: []sacloud.DNSRecordSet{
+				{Name: "@", Type: "TXT", RData: "quote\" backslash\\ tab\t é raw é", TTL: 3600},
+			},
+		},
+		"relative_rdata_names": {
+			text: `
+$ORIGIN example.com.
+www          IN CNAME web
+@            IN MX    10 mail
+@            IN NS    ns1.example.net.
+_sip._tcp    IN SRV   10 20 5060 sip
+1            IN PTR   @
+$ORIGIN sub.example.com.
+alias        IN CNAME host
+`,
+			records: []sacloud.DNSRecordSet{
+				{Name: "www", Type: "CNAME", RData: "web.example.com.", TTL: 3600},
+				{Name: "@", Type: "MX", RData: "10 mail.example.com.", TTL: 3600},
+				{Name: "@", Type: "NS", RData: "ns1.example.net.", TTL: 3600},
+				{Name: "_sip._tcp", Type: "SRV", RData: "10 20 5060 sip.example.com.", TTL: 3600},
+				{Name: "1", Type: "PTR", RData: "example.com.", TTL: 3600},
+				{Name: "alias.sub", Type: "CNAME", RData: "host.sub.example.com.", TTL: 3600},
+			},
+		},
+		"out_of_zone": {
+			text: `www.example.net. IN A 192.0.2.1`,
+			err:  true,
+		},
+		"unsupported_type": {
+			text: `@ IN HINFO "cpu" "os"`,
+			err:  true,
+		},
+		"unbalanced_parentheses": {
+			text: `@ IN TXT ( "foo"`,
+			err:  true,
+		},
+		"unterminated_quote": {
+			text: `@ IN TXT "foo`,
+			err:  true,
+		},
+		"invalid_escape": {
+			text: `@ IN TXT "\25x"`,
+			err:  true,
+		},
+	}
+
+	for name, c := range cases {
+		records, err := parseDNSZoneFile("example.com", c.text)
+		if c.err {
+			if err == nil {
+				t.Errorf("%s: expected error, got %#v", name, records)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", name, err)
+			continue
+		}
+		if !reflect.DeepEqual(records, c.records) {
+			t.Errorf("%s: expected %#v, got %#v", name, c.records, records)
+		}
+	}
+}
+
+func TestSakuraCloudExportDNSZoneFile(t *testing.T) {
+	records := []sacloud.DNSRecordSet{
+		{Name: "@", Type: "A", RData: "192.0.2.1", TTL: 3600},
+		{Name: "@", Type: "MX", RData: "10 mail.example.com.", TTL: 300},
+		{Name: "txt", Type: "TXT", RData: "quote\" tab\t é", TTL: 3600},
+	}
+	expected := "$ORIGIN example.com.\n" +
+		"@\t3600\tIN\tA\t192.0.2.1\n" +
+		"@\t300\tIN\tMX\t10 mail.example.com.\n" +
+		"txt\t3600\tIN\tTXT\t\"quote\\\" tab\\009 \\195\\169\"\n"
+
+	if zoneFile := exportDNSZoneFile("example.com", records); zoneFile != expected {
+		t.Errorf("expected %q, got %q", expected, zoneFile)
+	}
+}
+
+func TestSakuraCloudDNSZoneFileRoundTrip(t *testing.T) {
+	records := []sacloud.DNSRecordSet{
+		{Name: "@", Type: "A", RData: "192.0.2.1", TTL: 3600},
+		{Name: "www", Type: "CNAME", RData: "example.com.", TTL: 60},
+		{Name: "_sip._tcp", Type: "SRV", RData: "10 20 5060 sip.example.com.", TTL: 3600},
+		{Name: "txt", Type: "TXT", RData: "non-ascii é 日本語, tab\t, quote\", backslash\\, semicolon;", TTL: 3600},
+		{Name: "long", Type: "TXT", RData: strings.Repeat("a", 300), TTL: 3600},
+		{Name: "empty", Type: "TXT", RData: "", TTL: 3600},
+	}
+
+	zoneFile := exportDNSZoneFile("example.com", records)
+	parsed, err := parseDNSZoneFile("example.com", zoneFile)
+	if err != nil {
+		t.Fatalf("unexpected error: %s\n%s", err, zoneFile)
+	}
+	if !reflect.DeepEqual(parsed, records) {
+		t.Errorf("expected %#v, got %#v", records, parsed)
+	}
+}
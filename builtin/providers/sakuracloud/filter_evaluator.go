@@ -0,0 +1,212 @@
+package sakuracloud
+
+import (
+	"fmt"
+	"github.com/sacloud/libsacloud/sacloud"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// filterRule is a rule of packet filters and VPC router firewalls in a common form.
+// Empty fields match anything.
+type filterRule struct {
+	protocol    string
+	allow       bool
+	sourceNW    string
+	sourcePort  string
+	destNW      string
+	destPort    string
+	description string
+}
+
+// filterFlow is a packet to evaluate with filterRules.
+// Empty sourceIP/destIP and negative ports mean "unknown", which only matches rules without the condition.
+type filterFlow struct {
+	protocol   string
+	sourceIP   net.IP
+	sourcePort int
+	destIP     net.IP
+	destPort   int
+}
+
+func (f *filterFlow) String() string {
+	format := func(ip net.IP, port int) string {
+		s := "*"
+		if ip != nil {
+			s = ip.String()
+		}
+		if port >= 0 {
+			s += ":" + strconv.Itoa(port)
+		}
+		return s
+	}
+	return fmt.Sprintf("%s %s -> %s", f.protocol, format(f.sourceIP, f.sourcePort), format(f.destIP, f.destPort))
+}
+
+func filterRulesFromPacketFilterExpressions(expressions []*sacloud.PacketFilterExpression) []*filterRule {
+	rules := []*filterRule{}
+	for _, exp := range expressions {
+		rules = append(rules, &filterRule{
+			protocol:    exp.Protocol,
+			allow:       exp.Action == "allow",
+			sourceNW:    exp.SourceNetwork,
+			sourcePort:  exp.SourcePort,
+			destPort:    exp.DestinationPort,
+			description: exp.Description,
+		})
+	}
+	return rules
+}
+
+func filterRulesFromVPCRouterFirewallRules(firewallRules []*sacloud.VPCRouterFirewallRule) []*filterRule {
+	rules := []*filterRule{}
+	for _, r := range firewallRules {
+		rules = append(rules, &filterRule{
+			protocol:    r.Protocol,
+			allow:       r.Action == "allow",
+			sourceNW:    r.SourceNetwork,
+			sourcePort:  r.SourcePort,
+			destNW:      r.DestinationNetwork,
+			destPort:    r.DestinationPort,
+			description: r.Description,
+		})
+	}
+	return rules
+}
+
+// evaluateFilterRules returns whether the flow is allowed and the index of the first matched rule.
+// The index is -1 and defaultAllow is returned when no rule matches.
+func evaluateFilterRules(rules []*filterRule, flow *filterFlow, defaultAllow bool) (bool, int, error) {
+	for i, rule := range rules {
+		matched, err := rule.match(flow)
+		if err != nil {
+			return false, -1, fmt.Errorf("rule %d: %s", i, err)
+		}
+		if matched {
+			return rule.allow, i, nil
+		}
+	}
+	return defaultAllow, -1, nil
+}
+
+func (r *filterRule) match(flow *filterFlow) (bool, error) {
+	switch r.protocol {
+	case "ip":
+		// matches any protocol
+	case "fragment":
+		if flow.protocol != "fragment" {
+			return false, nil
+		}
+	default:
+		if r.protocol != flow.protocol {
+			return false, nil
+		}
+	}
+
+	if matched, err := matchFilterNetwork(r.sourceNW, flow.sourceIP); err != nil || !matched {
+		return false, err
+	}
+	if matched, err := matchFilterNetwork(r.destNW, flow.destIP); err != nil || !matched {
+		return false, err
+	}
+
+	// ports are only evaluated for tcp/udp
+	if r.protocol == "tcp" || r.protocol == "udp" {
+		if matched, err := matchFilterPort(r.sourcePort, flow.sourcePort); err != nil || !matched {
+			return false, err
+		}
+		if matched, err := matchFilterPort(r.destPort, flow.destPort); err != nil || !matched {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// matchFilterNetwork supports an address, "address/prefix length", "address/netmask" and "from-to" address range
+func matchFilterNetwork(network string, ip net.IP) (bool, error) {
+	if network == "" {
+		return true, nil
+	}
+	if ip == nil {
+		return false, nil
+	}
+
+	if strings.Contains(network, "-") {
+		parts := strings.SplitN(network, "-", 2)
+		from, to := net.ParseIP(parts[0]).To4(), net.ParseIP(parts[1]).To4()
+		if from == nil || to == nil {
+			return false, fmt.Errorf("invalid network %q", network)
+		}
+		target := ip.To4()
+		return target != nil && compareIPv4(from, target) <= 0 && compareIPv4(target, to) <= 0, nil
+	}
+
+	parts := strings.SplitN(network, "/", 2)
+	address := net.ParseIP(parts[0]).To4()
+	if address == nil {
+		return false, fmt.Errorf("invalid network %q", network)
+	}
+	if len(parts) == 1 {
+		return address.Equal(ip), nil
+	}
+
+	var mask net.IPMask
+	if bits, err := strconv.Atoi(parts[1]); err == nil {
+		mask = net.CIDRMask(bits, 32)
+	} else if m := net.ParseIP(parts[1]).To4(); m != nil {
+		mask = net.IPMask(m)
+	}
+	if mask == nil {
+		return false, fmt.Errorf("invalid network %q", network)
+	}
+	return (&net.IPNet{IP: address.Mask(mask), Mask: mask}).Contains(ip), nil
+}
+
+// matchFilterPort supports a port, "from-to" range and "0xPPPP/0xMMMM" masked range
+func matchFilterPort(ports string, port int) (bool, error) {
+	if ports == "" {
+		return true, nil
+	}
+	if port < 0 {
+		return false, nil
+	}
+
+	if strings.HasPrefix(ports, "0x") {
+		parts := strings.SplitN(ports, "/", 2)
+		if len(parts) != 2 {
+			return false, fmt.Errorf("invalid port %q", ports)
+		}
+		value, err1 := strconv.ParseUint(parts[0], 0, 16)
+		mask, err2 := strconv.ParseUint(parts[1], 0, 16)
+		if err1 != nil || err2 != nil {
+			return false, fmt.Errorf("invalid port %q", ports)
+		}
+		return uint64(port)&mask == value&mask, nil
+	}
+
+	parts := strings.SplitN(ports, "-", 2)
+	from, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return false, fmt.Errorf("invalid port %q", ports)
+	}
+	to := from
+	if len(parts) == 2 {
+		if to, err = strconv.Atoi(parts[1]); err != nil {
+			return false, fmt.Errorf("invalid port %q", ports)
+		}
+	}
+	return from <= port && port <= to, nil
+}
+
+func compareIPv4(a, b net.IP) int {
+	for i := 0; i < net.IPv4len; i++ {
+		if a[i] != b[i] {
+			if a[i] < b[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
@@ -0,0 +1,80 @@
+package sakuracloud
+
+import (
+	"net"
+	"testing"
+)
+
+func TestSakuraCloudEvaluateFilterRules(t *testing.T) {
+	rules := []*filterRule{
+		{protocol: "tcp", allow: true, sourceNW: "192.0.2.0/24", destPort: "22"},
+		{protocol: "tcp", allow: true, sourceNW: "198.51.100.0/255.255.255.0", destPort: "1024-2048"},
+		{protocol: "udp", allow: true, destPort: "0x0035/0xffff"},
+		{protocol: "icmp", allow: true},
+		{protocol: "fragment", allow: true, sourceNW: "203.0.113.1"},
+		{protocol: "tcp", allow: true, sourceNW: "203.0.113.10-203.0.113.20", destNW: "10.0.0.0/8"},
+		{protocol: "ip", allow: false},
+	}
+
+	cases := map[string]struct {
+		flow    *filterFlow
+		allowed bool
+		matched int
+	}{
+		"ssh_from_office": {
+			flow:    &filterFlow{protocol: "tcp", sourceIP: net.ParseIP("192.0.2.10"), sourcePort: 50000, destPort: 22},
+			allowed: true,
+			matched: 0,
+		},
+		"ssh_from_outside": {
+			flow:    &filterFlow{protocol: "tcp", sourceIP: net.ParseIP("192.0.3.10"), sourcePort: 50000, destPort: 22},
+			allowed: false,
+			matched: 6,
+		},
+		"netmask_range": {
+			flow:    &filterFlow{protocol: "tcp", sourceIP: net.ParseIP("198.51.100.1"), sourcePort: -1, destPort: 2048},
+			allowed: true,
+			matched: 1,
+		},
+		"masked_port": {
+			flow:    &filterFlow{protocol: "udp", sourcePort: -1, destPort: 53},
+			allowed: true,
+			matched: 2,
+		},
+		"unknown_port": {
+			flow:    &filterFlow{protocol: "tcp", sourceIP: net.ParseIP("192.0.2.10"), sourcePort: -1, destPort: -1},
+			allowed: false,
+			matched: 6,
+		},
+		"icmp": {
+			flow:    &filterFlow{protocol: "icmp", sourcePort: -1, destPort: -1},
+			allowed: true,
+			matched: 3,
+		},
+		"fragment": {
+			flow:    &filterFlow{protocol: "fragment", sourceIP: net.ParseIP("203.0.113.1"), sourcePort: -1, destPort: -1},
+			allowed: true,
+			matched: 4,
+		},
+		"address_range": {
+			flow:    &filterFlow{protocol: "tcp", sourceIP: net.ParseIP("203.0.113.15"), sourcePort: -1, destIP: net.ParseIP("10.1.2.3"), destPort: 80},
+			allowed: true,
+			matched: 5,
+		},
+	}
+
+	for name, tc := range cases {
+		allowed, matched, err := evaluateFilterRules(rules, tc.flow, true)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %s", name, err)
+		}
+		if allowed != tc.allowed || matched != tc.matched {
+			t.Fatalf("%s: expected (%t, %d), got (%t, %d)", name, tc.allowed, tc.matched, allowed, matched)
+		}
+	}
+
+	allowed, matched, err := evaluateFilterRules(rules[:1], &filterFlow{protocol: "udp", sourcePort: -1, destPort: 53}, false)
+	if err != nil || allowed || matched != -1 {
+		t.Fatalf("default action: expected (false, -1), got (%t, %d, %v)", allowed, matched, err)
+	}
+}
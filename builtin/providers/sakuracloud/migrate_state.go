@@ -0,0 +1,94 @@
+package sakuracloud
+
+import (
+	"fmt"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/terraform"
+	"log"
+	"sort"
+	"strings"
+)
+
+// stateMigrateFunc rewrites the flatmap attributes of a state to the next schema version
+type stateMigrateFunc func(is *terraform.InstanceState, meta interface{}) error
+
+// migrateStateFunc returns schema.StateMigrateFunc which applies migrations from the version of the state.
+// migrations[n] migrates a state of version n to n+1, so SchemaVersion of the resource must be len(migrations).
+func migrateStateFunc(resourceType string, migrations ...stateMigrateFunc) schema.StateMigrateFunc {
+	return func(v int, is *terraform.InstanceState, meta interface{}) (*terraform.InstanceState, error) {
+		if is.Empty() || is.Attributes == nil {
+			log.Printf("[DEBUG] Empty %s state; nothing to migrate.", resourceType)
+			return is, nil
+		}
+		if v > len(migrations) {
+			return is, fmt.Errorf("Unexpected schema version of %s: %d", resourceType, v)
+		}
+
+		for ; v < len(migrations); v++ {
+			// values may contain secrets such as notify_slack_webhook, so only the keys are logged
+			log.Printf("[DEBUG] Migrating %s state v%d to v%d: %v", resourceType, v, v+1, stateAttributeKeys(is))
+			if err := migrations[v](is, meta); err != nil {
+				return is, err
+			}
+		}
+		log.Printf("[DEBUG] Migrated %s state: %v", resourceType, stateAttributeKeys(is))
+		return is, nil
+	}
+}
+
+// setToListStateAttribute converts a set attribute in the state to a list attribute,
+// replacing the hash codes of the elements with list indexes.
+func setToListStateAttribute(key string) stateMigrateFunc {
+	return func(is *terraform.InstanceState, _ interface{}) error {
+		prefix := key + "."
+		hashes := []string{}
+		found := map[string]bool{}
+		for k := range is.Attributes {
+			if !strings.HasPrefix(k, prefix) {
+				continue
+			}
+			hash := strings.SplitN(strings.TrimPrefix(k, prefix), ".", 2)[0]
+			if hash == "#" || found[hash] {
+				continue
+			}
+			found[hash] = true
+			hashes = append(hashes, hash)
+		}
+		sort.Strings(hashes)
+
+		indexes := map[string]int{}
+		for i, hash := range hashes {
+			indexes[hash] = i
+		}
+
+		converted := map[string]string{}
+		for k, value := range is.Attributes {
+			if !strings.HasPrefix(k, prefix) {
+				continue
+			}
+			parts := strings.SplitN(strings.TrimPrefix(k, prefix), ".", 2)
+			if parts[0] == "#" {
+				continue
+			}
+			newKey := fmt.Sprintf("%s%d", prefix, indexes[parts[0]])
+			if len(parts) == 2 {
+				newKey += "." + parts[1]
+			}
+			converted[newKey] = value
+			delete(is.Attributes, k)
+		}
+		for k, value := range converted {
+			is.Attributes[k] = value
+		}
+		return nil
+	}
+}
+
+func stateAttributeKeys(is *terraform.InstanceState) []string {
+	keys := make([]string, 0, len(is.Attributes))
+	for k := range is.Attributes {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,100 @@
+package sakuracloud
+
+import (
+	"github.com/hashicorp/terraform/terraform"
+	"reflect"
+	"testing"
+)
+
+func TestSakuraCloudSimpleMonitorMigrateState(t *testing.T) {
+	cases := map[string]struct {
+		StateVersion int
+		Attributes   map[string]string
+		Expected     map[string]string
+	}{
+		"v0_1_dns": {
+			StateVersion: 0,
+			Attributes: map[string]string{
+				"target":                                 "sakuracloud.com",
+				"health_check.#":                         "1",
+				"health_check.2049235632.protocol":       "dns",
+				"health_check.2049235632.qname":          "sakuracloud.com",
+				"health_check.2049235632.excepcted_data": "192.0.2.1",
+			},
+			// excepcted_data is kept so that configurations still using it have no diff
+			Expected: map[string]string{
+				"target":                        "sakuracloud.com",
+				"health_check.#":                "1",
+				"health_check.0.protocol":       "dns",
+				"health_check.0.qname":          "sakuracloud.com",
+				"health_check.0.excepcted_data": "192.0.2.1",
+			},
+		},
+		"v0_1_http": {
+			StateVersion: 0,
+			Attributes: map[string]string{
+				"target":                           "sakuracloud.com",
+				"health_check.#":                   "1",
+				"health_check.1234567890.protocol": "http",
+				"health_check.1234567890.path":     "/",
+				"health_check.1234567890.status":   "200",
+			},
+			Expected: map[string]string{
+				"target":                  "sakuracloud.com",
+				"health_check.#":          "1",
+				"health_check.0.protocol": "http",
+				"health_check.0.path":     "/",
+				"health_check.0.status":   "200",
+			},
+		},
+		"v1_noop": {
+			StateVersion: 1,
+			Attributes: map[string]string{
+				"health_check.#":          "1",
+				"health_check.0.protocol": "ping",
+			},
+			Expected: map[string]string{
+				"health_check.#":          "1",
+				"health_check.0.protocol": "ping",
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		is := &terraform.InstanceState{
+			ID:         "112233445566",
+			Attributes: tc.Attributes,
+		}
+		is, err := resourceSakuraCloudSimpleMonitor().MigrateState(tc.StateVersion, is, nil)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %s", name, err)
+		}
+		if !reflect.DeepEqual(is.Attributes, tc.Expected) {
+			t.Fatalf("%s: bad migrated attributes:\n%#v\nexpected:\n%#v", name, is.Attributes, tc.Expected)
+		}
+	}
+}
+
+func TestSakuraCloudGSLBMigrateState(t *testing.T) {
+	is := &terraform.InstanceState{
+		ID: "112233445566",
+		Attributes: map[string]string{
+			"health_check.#":                     "1",
+			"health_check.1802742300.protocol":   "http",
+			"health_check.1802742300.delay_loop": "10",
+		},
+	}
+	expected := map[string]string{
+		"health_check.#":            "1",
+		"health_check.0.protocol":   "http",
+		"health_check.0.delay_loop": "10",
+	}
+
+	is, err := resourceSakuraCloudGSLB().MigrateState(0, is, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !reflect.DeepEqual(is.Attributes, expected) {
+		t.Fatalf("bad migrated attributes:\n%#v\nexpected:\n%#v", is.Attributes, expected)
+	}
+}
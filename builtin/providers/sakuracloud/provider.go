@@ -45,22 +45,25 @@ func Provider() terraform.ResourceProvider {
 			},
 		},
 		DataSourcesMap: map[string]*schema.Resource{
-			"sakuracloud_archive":        dataSourceSakuraCloudArchive(),
-			"sakuracloud_bridge":         dataSourceSakuraCloudBridge(),
-			"sakuracloud_cdrom":          dataSourceSakuraCloudCDROM(),
-			"sakuracloud_database":       dataSourceSakuraCloudDatabase(),
-			"sakuracloud_disk":           dataSourceSakuraCloudDisk(),
-			"sakuracloud_dns":            dataSourceSakuraCloudDNS(),
-			"sakuracloud_gslb":           dataSourceSakuraCloudGSLB(),
-			"sakuracloud_internet":       dataSourceSakuraCloudInternet(),
-			"sakuracloud_load_balancer":  dataSourceSakuraCloudLoadBalancer(),
-			"sakuracloud_note":           dataSourceSakuraCloudNote(),
-			"sakuracloud_packet_filter":  dataSourceSakuraCloudPacketFilter(),
-			"sakuracloud_simple_monitor": dataSourceSakuraCloudSimpleMonitor(),
-			"sakuracloud_server":         dataSourceSakuraCloudServer(),
-			"sakuracloud_ssh_key":        dataSourceSakuraCloudSSHKey(),
-			"sakuracloud_subnet":         dataSourceSakuraCloudSubnet(),
-			"sakuracloud_switch":         dataSourceSakuraCloudSwitch(),
+			"sakuracloud_archive":                  dataSourceSakuraCloudArchive(),
+			"sakuracloud_auto_backup_archives":     dataSourceSakuraCloudAutoBackupArchives(),
+			"sakuracloud_bridge":                   dataSourceSakuraCloudBridge(),
+			"sakuracloud_cdrom":                    dataSourceSakuraCloudCDROM(),
+			"sakuracloud_database":                 dataSourceSakuraCloudDatabase(),
+			"sakuracloud_disk":                     dataSourceSakuraCloudDisk(),
+			"sakuracloud_dns":                      dataSourceSakuraCloudDNS(),
+			"sakuracloud_gslb":                     dataSourceSakuraCloudGSLB(),
+			"sakuracloud_internet":                 dataSourceSakuraCloudInternet(),
+			"sakuracloud_load_balancer":            dataSourceSakuraCloudLoadBalancer(),
+			"sakuracloud_note":                     dataSourceSakuraCloudNote(),
+			"sakuracloud_packet_filter":            dataSourceSakuraCloudPacketFilter(),
+			"sakuracloud_packet_filter_evaluation": dataSourceSakuraCloudPacketFilterEvaluation(),
+			"sakuracloud_simple_monitor":           dataSourceSakuraCloudSimpleMonitor(),
+			"sakuracloud_server":                   dataSourceSakuraCloudServer(),
+			"sakuracloud_server_vnc_info":          dataSourceSakuraCloudServerVNCInfo(),
+			"sakuracloud_ssh_key":                  dataSourceSakuraCloudSSHKey(),
+			"sakuracloud_subnet":                   dataSourceSakuraCloudSubnet(),
+			"sakuracloud_switch":                   dataSourceSakuraCloudSwitch(),
 		},
 		ResourcesMap: map[string]*schema.Resource{
 			"sakuracloud_auto_backup":                    resourceSakuraCloudAutoBackup(),
@@ -69,14 +72,18 @@ func Provider() terraform.ResourceProvider {
 			"sakuracloud_disk":                           resourceSakuraCloudDisk(),
 			"sakuracloud_dns":                            resourceSakuraCloudDNS(),
 			"sakuracloud_dns_record":                     resourceSakuraCloudDNSRecord(),
+			"sakuracloud_dns_records":                    resourceSakuraCloudDNSRecords(),
 			"sakuracloud_gslb":                           resourceSakuraCloudGSLB(),
 			"sakuracloud_gslb_server":                    resourceSakuraCloudGSLBServer(),
 			"sakuracloud_internet":                       resourceSakuraCloudInternet(),
+			"sakuracloud_ip_allocation":                  resourceSakuraCloudIPAllocation(),
 			"sakuracloud_load_balancer":                  resourceSakuraCloudLoadBalancer(),
 			"sakuracloud_load_balancer_vip":              resourceSakuraCloudLoadBalancerVIP(),
 			"sakuracloud_load_balancer_server":           resourceSakuraCloudLoadBalancerServer(),
 			"sakuracloud_note":                           resourceSakuraCloudNote(),
 			"sakuracloud_packet_filter":                  resourceSakuraCloudPacketFilter(),
+			"sakuracloud_packet_filter_attachment":       resourceSakuraCloudPacketFilterAttachment(),
+			"sakuracloud_packet_filter_rules":            resourceSakuraCloudPacketFilterRules(),
 			"sakuracloud_simple_monitor":                 resourceSakuraCloudSimpleMonitor(),
 			"sakuracloud_server":                         resourceSakuraCloudServer(),
 			"sakuracloud_ssh_key":                        resourceSakuraCloudSSHKey(),
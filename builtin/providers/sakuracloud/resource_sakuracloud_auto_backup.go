@@ -21,7 +21,6 @@ func resourceSakuraCloudAutoBackup() *schema.Resource {
 			"name": {
 				Type:     schema.TypeString,
 				Required: true,
-				ForceNew: true,
 			},
 			"disk_id": {
 				Type:         schema.TypeString,
@@ -72,14 +71,11 @@ func resourceSakuraCloudAutoBackupCreate(d *schema.ResourceData, meta interface{
 	diskID := d.Get("disk_id").(string)
 	opts := client.AutoBackup.New(d.Get("name").(string), toSakuraCloudID(diskID))
 	opts.SetBackupMaximumNumberOfArchives(d.Get("max_backup_num").(int))
-	rawWeekdays := d.Get("weekdays").([]interface{})
-	if rawWeekdays != nil {
-		weekdays, err := expandStringListWithValidateInList("weekdays", rawWeekdays, sacloud.AllowAutoBackupWeekdays())
-		if err != nil {
-			return err
-		}
-		opts.SetBackupSpanWeekdays(weekdays)
+	weekdays, err := expandAutoBackupWeekdays(d)
+	if err != nil {
+		return err
 	}
+	opts.SetBackupSpanWeekdays(weekdays)
 
 	if description, ok := d.GetOk("description"); ok {
 		opts.Description = description.(string)
@@ -113,8 +109,16 @@ func resourceSakuraCloudAutoBackupRead(d *schema.ResourceData, meta interface{})
 
 	d.Set("name", autoBackup.Name)
 	d.Set("disk_id", autoBackup.Status.DiskID)
-	d.Set("max_backup_num", autoBackup.Settings.Autobackup.MaximumNumberOfArchives)
-	d.Set("weekdays", autoBackup.Settings.Autobackup.BackupSpanWeekdays)
+	if autoBackup.Settings != nil && autoBackup.Settings.Autobackup != nil {
+		d.Set("max_backup_num", autoBackup.Settings.Autobackup.MaximumNumberOfArchives)
+
+		// the API may return weekdays in a different order, so keep the configured order if they are the same
+		weekdays := autoBackup.Settings.Autobackup.BackupSpanWeekdays
+		current := expandStringList(d.Get("weekdays").([]interface{}))
+		if !isSameStringSet(current, weekdays) {
+			d.Set("weekdays", weekdays)
+		}
+	}
 
 	d.Set("description", autoBackup.Description)
 	d.Set("tags", autoBackup.Tags)
@@ -130,18 +134,23 @@ func resourceSakuraCloudAutoBackupUpdate(d *schema.ResourceData, meta interface{
 		client.Zone = zone.(string)
 	}
 
+	weekdays, err := expandAutoBackupWeekdays(d)
+	if err != nil {
+		return err
+	}
+
 	autoBackup, err := client.AutoBackup.Read(toSakuraCloudID(d.Id()))
 	if err != nil {
 		return fmt.Errorf("Couldn't find SakuraCloud AutoBackup resource: %s", err)
 	}
 
-	autoBackup.SetBackupMaximumNumberOfArchives(d.Get("max_backup_num").(int))
-	rawWeekdays := d.Get("weekdays").([]interface{})
-	if rawWeekdays != nil {
-		weekdays, err := expandStringListWithValidateInList("weekdays", rawWeekdays, sacloud.AllowAutoBackupWeekdays())
-		if err != nil {
-			return err
-		}
+	if d.HasChange("name") {
+		autoBackup.Name = d.Get("name").(string)
+	}
+	if d.HasChange("max_backup_num") {
+		autoBackup.SetBackupMaximumNumberOfArchives(d.Get("max_backup_num").(int))
+	}
+	if d.HasChange("weekdays") {
 		autoBackup.SetBackupSpanWeekdays(weekdays)
 	}
 
@@ -152,14 +161,13 @@ func resourceSakuraCloudAutoBackupUpdate(d *schema.ResourceData, meta interface{
 			autoBackup.Description = ""
 		}
 	}
-	rawTags := d.Get("tags").([]interface{})
-	if rawTags != nil {
-		autoBackup.Tags = expandStringList(rawTags)
+	if d.HasChange("tags") {
+		autoBackup.Tags = expandStringList(d.Get("tags").([]interface{}))
 	}
 
 	autoBackup, err = client.AutoBackup.Update(autoBackup.ID, autoBackup)
 	if err != nil {
-		return fmt.Errorf("Failed to create SakuraCloud AutoBackup resource: %s", err)
+		return fmt.Errorf("Error updating SakuraCloud AutoBackup resource: %s", err)
 	}
 
 	d.SetId(autoBackup.GetStrID())
@@ -181,3 +189,39 @@ func resourceSakuraCloudAutoBackupDelete(d *schema.ResourceData, meta interface{
 
 	return nil
 }
+
+func expandAutoBackupWeekdays(d *schema.ResourceData) ([]string, error) {
+	weekdays, err := expandStringListWithValidateInList("weekdays", d.Get("weekdays").([]interface{}), sacloud.AllowAutoBackupWeekdays())
+	if err != nil {
+		return nil, err
+	}
+	if len(weekdays) == 0 {
+		return nil, fmt.Errorf("%q must have at least one weekday", "weekdays")
+	}
+
+	found := map[string]bool{}
+	for _, w := range weekdays {
+		if found[w] {
+			return nil, fmt.Errorf("%q has duplicated weekday: %q", "weekdays", w)
+		}
+		found[w] = true
+	}
+	return weekdays, nil
+}
+
+func isSameStringSet(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	counts := map[string]int{}
+	for _, v := range a {
+		counts[v]++
+	}
+	for _, v := range b {
+		counts[v]--
+		if counts[v] < 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -36,6 +36,7 @@ func TestAccResourceSakuraCloudAutoBackup(t *testing.T) {
 			{
 				Config: testAccCheckSakuraCloudAutoBackupConfig_update,
 				Check: resource.ComposeTestCheckFunc(
+					testAccCheckSakuraCloudAutoBackupNotRecreated("sakuracloud_auto_backup.foobar", &autoBackup),
 					testAccCheckSakuraCloudAutoBackupExists("sakuracloud_auto_backup.foobar", &autoBackup),
 					resource.TestCheckResourceAttr("sakuracloud_auto_backup.foobar", "name", "name_after"),
 					resource.TestCheckResourceAttr("sakuracloud_auto_backup.foobar", "weekdays.#", "2"),
@@ -49,10 +50,42 @@ func TestAccResourceSakuraCloudAutoBackup(t *testing.T) {
 					resource.TestCheckResourceAttr("sakuracloud_auto_backup.foobar", "zone", "is1b"),
 				),
 			},
+			{
+				// change the schedule outside of terraform, then apply the same config
+				PreConfig: func() {
+					client := testAccProvider.Meta().(*api.Client)
+					client.Zone = "is1b"
+					autoBackup.SetBackupSpanWeekdays([]string{"sun"})
+					autoBackup.SetBackupMaximumNumberOfArchives(5)
+					if _, err := client.AutoBackup.Update(autoBackup.ID, &autoBackup); err != nil {
+						t.Fatal(err)
+					}
+				},
+				Config: testAccCheckSakuraCloudAutoBackupConfig_update,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("sakuracloud_auto_backup.foobar", "weekdays.#", "2"),
+					resource.TestCheckResourceAttr("sakuracloud_auto_backup.foobar", "weekdays.0", "thu"),
+					resource.TestCheckResourceAttr("sakuracloud_auto_backup.foobar", "weekdays.1", "fri"),
+					resource.TestCheckResourceAttr("sakuracloud_auto_backup.foobar", "max_backup_num", "2"),
+				),
+			},
 		},
 	})
 }
 
+func testAccCheckSakuraCloudAutoBackupNotRecreated(n string, autoBackup *sacloud.AutoBackup) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+		if rs.Primary.ID != autoBackup.GetStrID() {
+			return fmt.Errorf("AutoBackup is recreated: %s -> %s", autoBackup.GetStrID(), rs.Primary.ID)
+		}
+		return nil
+	}
+}
+
 func testAccCheckSakuraCloudAutoBackupExists(n string, auto_backup *sacloud.AutoBackup) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		rs, ok := s.RootModule().Resources[n]
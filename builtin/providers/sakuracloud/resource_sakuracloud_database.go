@@ -5,6 +5,7 @@ import (
 	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/sacloud/libsacloud/api"
 	"github.com/sacloud/libsacloud/sacloud"
+	"strconv"
 	"strings"
 )
 
@@ -46,11 +47,21 @@ func resourceSakuraCloudDatabase() *schema.Resource {
 			"user_name": {
 				Type:     schema.TypeString,
 				ForceNew: true,
-				Required: true,
+				Optional: true,
+				Computed: true,
 			},
 			"user_password": {
 				Type:     schema.TypeString,
-				Required: true,
+				Optional: true,
+				Computed: true,
+			},
+			// clone_from_database_id creates an independent copy, not a replication slave.
+			// The vendored libsacloud can't send replication settings, so slaves aren't supported.
+			"clone_from_database_id": {
+				Type:         schema.TypeString,
+				ForceNew:     true,
+				Optional:     true,
+				ValidateFunc: validateSakuracloudIDType,
 			},
 			"allow_networks": {
 				Type:     schema.TypeList,
@@ -63,6 +74,12 @@ func resourceSakuraCloudDatabase() *schema.Resource {
 				Default:      5432,
 				ValidateFunc: validateIntegerInRange(1024, 65535),
 			},
+			"backup_rotate": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      8,
+				ValidateFunc: validateIntegerInRange(1, 8),
+			},
 			"backup_time": {
 				Type:         schema.TypeString,
 				Required:     true,
@@ -122,15 +139,35 @@ func resourceSakuraCloudDatabaseCreate(d *schema.ResourceData, meta interface{})
 
 	var opts *sacloud.CreateDatabaseValue
 	dbType := d.Get("database_type").(string)
-	switch dbType {
-	case "postgresql":
-		opts = sacloud.NewCreatePostgreSQLDatabaseValue()
-		break
-	case "mariadb":
-		opts = sacloud.NewCreateMariaDBDatabaseValue()
-		break
-	default:
-		return fmt.Errorf("Unknown database_type [%s]", dbType)
+	sourceID, isClone := d.GetOk("clone_from_database_id")
+	if isClone {
+		// create as a copy of the source database
+		source, err := client.Database.Read(toSakuraCloudID(sourceID.(string)))
+		if err != nil {
+			return fmt.Errorf("Couldn't find SakuraCloud Database resource(clone_from_database_id): %s", err)
+		}
+		if sourceType := databaseTypeFromName(source.Remark.DBConf.Common.DatabaseName); sourceType != dbType {
+			return fmt.Errorf("'database_type' must be %q to clone Database(id:%s)", sourceType, sourceID.(string))
+		}
+		opts = sacloud.NewCloneDatabaseValue(source)
+	} else {
+		switch dbType {
+		case "postgresql":
+			opts = sacloud.NewCreatePostgreSQLDatabaseValue()
+			break
+		case "mariadb":
+			opts = sacloud.NewCreateMariaDBDatabaseValue()
+			break
+		default:
+			return fmt.Errorf("Unknown database_type [%s]", dbType)
+		}
+
+		if _, ok := d.GetOk("user_name"); !ok {
+			return fmt.Errorf("'user_name' required when clone_from_database_id is empty%s", "")
+		}
+		if _, ok := d.GetOk("user_password"); !ok {
+			return fmt.Errorf("'user_password' required when clone_from_database_id is empty%s", "")
+		}
 	}
 
 	opts.Name = d.Get("name").(string)
@@ -142,7 +179,6 @@ func resourceSakuraCloudDatabaseCreate(d *schema.ResourceData, meta interface{})
 		}
 	}
 	opts.ServicePort = fmt.Sprintf("%d", d.Get("port").(int))
-	//opts.BackupRotate = d.Get("backup_rotate").(int)
 	opts.BackupTime = d.Get("backup_time").(string)
 
 	opts.SwitchID = d.Get("switch_id").(string)
@@ -179,7 +215,14 @@ func resourceSakuraCloudDatabaseCreate(d *schema.ResourceData, meta interface{})
 		}
 	}
 
-	createDB := sacloud.CreateNewDatabase(opts)
+	var createDB *sacloud.Database
+	if isClone {
+		createDB = sacloud.CloneNewDatabase(opts)
+	} else {
+		createDB = sacloud.CreateNewDatabase(opts)
+	}
+	createDB.Settings.DBConf.Backup.Rotate = d.Get("backup_rotate").(int)
+
 	database, err := client.Database.Create(createDB)
 	if err != nil {
 		return fmt.Errorf("Failed to create SakuraCloud Database resource: %s", err)
@@ -260,14 +303,11 @@ func resourceSakuraCloudDatabaseUpdate(d *schema.ResourceData, meta interface{})
 		database.Settings.DBConf.Common.UserPassword = d.Get("user_password").(string)
 	}
 	if d.HasChange("allow_networks") {
-		if rawNetworks, ok := d.GetOk("allow_networks"); ok {
-			if rawNetworks != nil {
-				database.Settings.DBConf.Common.SourceNetwork = expandStringList(rawNetworks.([]interface{}))
-			} else {
-				database.Settings.DBConf.Common.SourceNetwork = nil
-			}
+		if rawNetworks, ok := d.GetOk("allow_networks"); ok && rawNetworks != nil {
+			database.Settings.DBConf.Common.SourceNetwork = expandStringList(rawNetworks.([]interface{}))
+		} else {
+			database.Settings.DBConf.Common.SourceNetwork = sacloud.SourceNetwork([]string{})
 		}
-
 	}
 	if d.HasChange("port") {
 		database.Settings.DBConf.Common.ServicePort = fmt.Sprintf("%d", d.Get("port").(int))
@@ -320,19 +360,20 @@ func resourceSakuraCloudDatabaseDelete(d *schema.ResourceData, meta interface{})
 
 func setDatabaseResourceData(d *schema.ResourceData, client *api.Client, data *sacloud.Database) error {
 
-	switch data.Remark.DBConf.Common.DatabaseName {
-	case "postgres":
-		d.Set("database_type", "postgresql")
-		break
-	case "MariaDB":
-		d.Set("database_type", "mariadb")
-		break
+	if dbType := databaseTypeFromName(data.Remark.DBConf.Common.DatabaseName); dbType != "" {
+		d.Set("database_type", dbType)
 	}
 
 	d.Set("name", data.Name)
-	d.Set("user_password", data.Settings.DBConf.Common.DefaultUser)
+	d.Set("user_name", data.Settings.DBConf.Common.DefaultUser)
 	d.Set("user_password", data.Settings.DBConf.Common.UserPassword)
 
+	if data.Remark.SourceAppliance != nil {
+		d.Set("clone_from_database_id", data.Remark.SourceAppliance.GetStrID())
+	} else {
+		d.Set("clone_from_database_id", "")
+	}
+
 	//plan
 	switch data.Plan.ID {
 	case int64(sacloud.DatabasePlan10G):
@@ -346,8 +387,9 @@ func setDatabaseResourceData(d *schema.ResourceData, client *api.Client, data *s
 
 	}
 
-	d.Set("allow_networks", data.Settings.DBConf.Common.SourceNetwork)
-	d.Set("port", data.Settings.DBConf.Common.ServicePort)
+	d.Set("allow_networks", []string(data.Settings.DBConf.Common.SourceNetwork))
+	port, _ := strconv.Atoi(data.Settings.DBConf.Common.ServicePort)
+	d.Set("port", port)
 
 	d.Set("backup_rotate", data.Settings.DBConf.Backup.Rotate)
 	d.Set("backup_time", data.Settings.DBConf.Backup.Time)
@@ -370,3 +412,13 @@ func setDatabaseResourceData(d *schema.ResourceData, client *api.Client, data *s
 	d.SetId(data.GetStrID())
 	return nil
 }
+
+func databaseTypeFromName(name string) string {
+	switch name {
+	case "postgres":
+		return "postgresql"
+	case "MariaDB":
+		return "mariadb"
+	}
+	return ""
+}
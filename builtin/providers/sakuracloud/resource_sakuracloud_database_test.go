@@ -35,7 +35,7 @@ func TestAccResourceSakuraCloudDatabase_WithSwitch(t *testing.T) {
 					resource.TestCheckResourceAttr("sakuracloud_database.foobar", "allow_networks.0", "192.168.11.0/24"),
 					resource.TestCheckResourceAttr("sakuracloud_database.foobar", "allow_networks.1", "192.168.12.0/24"),
 					resource.TestCheckResourceAttr("sakuracloud_database.foobar", "port", "33061"),
-					//resource.TestCheckResourceAttr("sakuracloud_database.foobar", "backup_rotate", "8"),
+					resource.TestCheckResourceAttr("sakuracloud_database.foobar", "backup_rotate", "8"),
 					resource.TestCheckResourceAttr("sakuracloud_database.foobar", "backup_time", "00:00"),
 					resource.TestCheckResourceAttr("sakuracloud_database.foobar", "ipaddress1", "192.168.11.101"),
 					resource.TestCheckResourceAttr("sakuracloud_database.foobar", "nw_mask_len", "24"),
@@ -60,7 +60,7 @@ func TestAccResourceSakuraCloudDatabase_WithSwitch(t *testing.T) {
 					resource.TestCheckResourceAttr("sakuracloud_database.foobar", "allow_networks.0", "192.168.110.0/24"),
 					resource.TestCheckResourceAttr("sakuracloud_database.foobar", "allow_networks.1", "192.168.120.0/24"),
 					resource.TestCheckResourceAttr("sakuracloud_database.foobar", "port", "33062"),
-					//resource.TestCheckResourceAttr("sakuracloud_database.foobar", "backup_rotate", "7"),
+					resource.TestCheckResourceAttr("sakuracloud_database.foobar", "backup_rotate", "7"),
 					resource.TestCheckResourceAttr("sakuracloud_database.foobar", "backup_time", "00:30"),
 					resource.TestCheckResourceAttr("sakuracloud_database.foobar", "ipaddress1", "192.168.11.101"),
 					resource.TestCheckResourceAttr("sakuracloud_database.foobar", "nw_mask_len", "24"),
@@ -71,6 +71,29 @@ func TestAccResourceSakuraCloudDatabase_WithSwitch(t *testing.T) {
 	})
 }
 
+func TestAccResourceSakuraCloudDatabase_Clone(t *testing.T) {
+	var master, clone sacloud.Database
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckSakuraCloudDatabaseDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckSakuraCloudDatabaseConfig_Clone,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckSakuraCloudDatabaseExists("sakuracloud_database.master", &master),
+					testAccCheckSakuraCloudDatabaseExists("sakuracloud_database.clone", &clone),
+					resource.TestCheckResourceAttr("sakuracloud_database.clone", "database_type", "mariadb"),
+					resource.TestCheckResourceAttrPair(
+						"sakuracloud_database.clone", "clone_from_database_id",
+						"sakuracloud_database.master", "id"),
+					resource.TestCheckResourceAttr("sakuracloud_database.clone", "backup_rotate", "3"),
+				),
+			},
+		},
+	})
+}
+
 func testAccCheckSakuraCloudDatabaseExists(n string, database *sacloud.Database) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		rs, ok := s.RootModule().Resources[n]
@@ -172,6 +195,7 @@ resource "sakuracloud_database" "foobar" {
 
     port = 33062
 
+    backup_rotate = 7
     backup_time = "00:30"
 
     name = "name_after"
@@ -185,3 +209,37 @@ resource "sakuracloud_database" "foobar" {
 
     zone = "is1b"
 }`
+
+const testAccCheckSakuraCloudDatabaseConfig_Clone = `
+resource "sakuracloud_switch" "sw" {
+    name = "sw"
+    zone = "is1b"
+}
+resource "sakuracloud_database" "master" {
+    database_type = "mariadb"
+    user_name = "defuser"
+    user_password = "DatabasePasswordUser397"
+    backup_time = "00:00"
+
+    switch_id = "${sakuracloud_switch.sw.id}"
+    ipaddress1 = "192.168.11.101"
+    nw_mask_len = 24
+    default_route = "192.168.11.1"
+
+    name = "master"
+    zone = "is1b"
+}
+resource "sakuracloud_database" "clone" {
+    database_type = "mariadb"
+    clone_from_database_id = "${sakuracloud_database.master.id}"
+    backup_rotate = 3
+    backup_time = "00:30"
+
+    switch_id = "${sakuracloud_switch.sw.id}"
+    ipaddress1 = "192.168.11.102"
+    nw_mask_len = 24
+    default_route = "192.168.11.1"
+
+    name = "clone"
+    zone = "is1b"
+}`
@@ -1,12 +1,15 @@
 package sakuracloud
 
 import (
+	"bytes"
 	"fmt"
 	"github.com/docker/go-units"
+	"github.com/hashicorp/terraform/helper/hashcode"
 	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/sacloud/libsacloud/api"
 	"github.com/sacloud/libsacloud/sacloud"
 	"log"
+	"strings"
 	"time"
 )
 
@@ -43,29 +46,64 @@ func resourceSakuraCloudDisk() *schema.Resource {
 				}),
 			},
 			"source_archive_id": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ForceNew:         true,
+				ConflictsWith:    []string{"source_disk_id", "reinstall_from_archive_id", "reinstall_from_disk_id"},
+				ValidateFunc:     validateSakuracloudIDType,
+				DiffSuppressFunc: suppressDiskSourceDiff,
+			},
+			"source_disk_id": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ForceNew:         true,
+				ConflictsWith:    []string{"source_archive_id", "reinstall_from_archive_id", "reinstall_from_disk_id"},
+				ValidateFunc:     validateSakuracloudIDType,
+				DiffSuppressFunc: suppressDiskSourceDiff,
+			},
+			"reinstall_from_archive_id": {
 				Type:          schema.TypeString,
-				ForceNew:      true,
 				Optional:      true,
-				ConflictsWith: []string{"source_disk_id"},
+				ConflictsWith: []string{"source_archive_id", "source_disk_id", "reinstall_from_disk_id"},
 				ValidateFunc:  validateSakuracloudIDType,
 			},
-			"source_disk_id": {
+			"reinstall_from_disk_id": {
 				Type:          schema.TypeString,
-				ForceNew:      true,
 				Optional:      true,
-				ConflictsWith: []string{"source_archive_id"},
+				ConflictsWith: []string{"source_archive_id", "source_disk_id", "reinstall_from_archive_id"},
 				ValidateFunc:  validateSakuracloudIDType,
 			},
+			"restore_from_backup": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateBackupGeneration,
+			},
 			"size": {
 				Type:     schema.TypeInt,
 				Optional: true,
 				ForceNew: true,
 				Default:  20,
 			},
+			"distant_from": {
+				Type:     schema.TypeList,
+				Optional: true,
+				ForceNew: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+				// ! Current terraform(v0.7) is not support to array validation !
+				// ValidateFunc: validateSakuracloudIDArrayType,
+			},
 			"server_id": {
 				Type:     schema.TypeString,
 				Computed: true, //ReadOnly
 			},
+			"storage_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"storage_class": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
 
 			"description": {
 				Type:     schema.TypeString,
@@ -113,6 +151,10 @@ func resourceSakuraCloudDisk() *schema.Resource {
 				// ! Current terraform(v0.7) is not support to array validation !
 				// ValidateFunc: validateSakuracloudIDArrayType,
 			},
+			"last_edit": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
 		},
 	}
 }
@@ -143,16 +185,18 @@ func resourceSakuraCloudDiskCreate(d *schema.ResourceData, meta interface{}) err
 
 	opts.Connection = sacloud.EDiskConnection(d.Get("connector").(string))
 
-	archiveID, ok := d.GetOk("source_archive_id")
-	if ok {
-		opts.SetSourceArchive(toSakuraCloudID(archiveID.(string)))
+	archiveID, diskID := expandDiskSource(d)
+	if archiveID != "" {
+		opts.SetSourceArchive(toSakuraCloudID(archiveID))
 	}
-	diskID, ok := d.GetOk("source_disk_id")
-	if ok {
-		opts.SetSourceDisk(toSakuraCloudID(diskID.(string)))
+	if diskID != "" {
+		opts.SetSourceDisk(toSakuraCloudID(diskID))
 	}
 
 	opts.SizeMB = d.Get("size").(int) * units.GiB / units.MiB
+	if distantFrom := expandDiskDistantFrom(d); len(distantFrom) > 0 {
+		opts.SetDistantFrom(distantFrom)
+	}
 	if description, ok := d.GetOk("description"); ok {
 		opts.Description = description.(string)
 	}
@@ -172,40 +216,11 @@ func resourceSakuraCloudDiskCreate(d *schema.ResourceData, meta interface{}) err
 	}
 
 	//edit disk
-	diskEditConfig := client.Disk.NewCondig()
-	if hostName, ok := d.GetOk("hostname"); ok {
-		diskEditConfig.SetHostName(hostName.(string))
-	}
-	if password, ok := d.GetOk("password"); ok {
-		diskEditConfig.SetPassword(password.(string))
-	}
-	if sshKeyIDs, ok := d.GetOk("ssh_key_ids"); ok {
-		ids := expandStringList(sshKeyIDs.([]interface{}))
-		diskEditConfig.SetSSHKeys(ids)
-	}
-
-	if disablePasswordAuth, ok := d.GetOk("disable_pw_auth"); ok {
-		diskEditConfig.SetDisablePWAuth(disablePasswordAuth.(bool))
-	}
-
-	if noteIDs, ok := d.GetOk("note_ids"); ok {
-		ids := expandStringList(noteIDs.([]interface{}))
-		diskEditConfig.SetNotes(ids)
-	}
-
-	// call disk edit API
-	res, err := client.Disk.CanEditDisk(disk.ID)
+	err = editDisk(client, disk.ID, expandDiskEditValue(client, d))
 	if err != nil {
-		return fmt.Errorf("Failed to check CanEditDisk: %s", err)
-	}
-	if res {
-		_, err = client.Disk.Config(disk.ID, diskEditConfig)
-		if err != nil {
-			return fmt.Errorf("Error editting SakuraCloud DiskConfig: %s", err)
-		}
-	} else {
-		log.Printf("[WARN] Disk[%d] does not support modify disk", disk.ID)
+		return err
 	}
+	d.Set("last_edit", diskEditFingerprint(d, disk.ReinstallCount))
 
 	server_id, ok := d.GetOk("server_id")
 	if ok {
@@ -233,6 +248,18 @@ func resourceSakuraCloudDiskRead(d *schema.ResourceData, meta interface{}) error
 		return fmt.Errorf("Couldn't find SakuraCloud Disk resource: %s", err)
 	}
 
+	// settings of disk edit can't be read from API.
+	// If the disk was reinstalled outside of terraform, clear them to apply again.
+	if lastEdit, ok := d.GetOk("last_edit"); ok && lastEdit.(string) != diskEditFingerprint(d, disk.ReinstallCount) {
+		log.Printf("[WARN] Disk[%d] was reinstalled outside of terraform, settings of disk edit will be applied again", disk.ID)
+		d.Set("hostname", "")
+		d.Set("password", "")
+		d.Set("ssh_key_ids", []string{})
+		d.Set("disable_pw_auth", false)
+		d.Set("note_ids", []string{})
+		d.Set("last_edit", "")
+	}
+
 	return setDiskResourceData(d, client, disk)
 }
 
@@ -244,11 +271,37 @@ func resourceSakuraCloudDiskUpdate(d *schema.ResourceData, meta interface{}) err
 		client.Zone = zone.(string)
 	}
 
+	// source_archive_id/source_disk_id are ForceNew, so only reinstall_from_archive_id/reinstall_from_disk_id reinstall the disk
+	isSourceChanged := false
+	if d.HasChange("reinstall_from_archive_id") || d.HasChange("reinstall_from_disk_id") {
+		oldArchiveID, newArchiveID := d.GetChange("reinstall_from_archive_id")
+		oldDiskID, newDiskID := d.GetChange("reinstall_from_disk_id")
+		if oldArchiveID.(string) == "" && oldDiskID.(string) == "" {
+			// switched from source_archive_id/source_disk_id
+			oldArchiveID, oldDiskID = d.Get("source_archive_id"), d.Get("source_disk_id")
+		}
+		isSourceChanged = (newArchiveID.(string) != "" || newDiskID.(string) != "") &&
+			(newArchiveID.(string) != oldArchiveID.(string) || newDiskID.(string) != oldDiskID.(string))
+	}
+
 	disk, err := client.Disk.Read(toSakuraCloudID(d.Id()))
 	if err != nil {
 		return fmt.Errorf("Couldn't find SakuraCloud Disk resource: %s", err)
 	}
 
+	// resolve the backup archive before stopping the server
+	var backupArchive *sacloud.Archive
+	if generation := d.Get("restore_from_backup").(string); d.HasChange("restore_from_backup") && generation != "" {
+		if isSourceChanged {
+			return fmt.Errorf("restore_from_backup can't be changed with reinstall_from_archive_id/reinstall_from_disk_id at the same time")
+		}
+		backupArchive, err = findAutoBackupArchive(client, disk.ID, generation)
+		if err != nil {
+			return err
+		}
+	}
+	isRestore := backupArchive != nil
+
 	// has server_id and server is up,shutdown
 	isRunning := disk.Server != nil && disk.Server.Instance.IsUp()
 	isDiskConfigChanged := false
@@ -257,7 +310,7 @@ func resourceSakuraCloudDiskUpdate(d *schema.ResourceData, meta interface{}) err
 		isDiskConfigChanged = true
 	}
 
-	if isRunning && isDiskConfigChanged {
+	if isRunning && (isDiskConfigChanged || isSourceChanged || isRestore) {
 		_, err := client.Server.Shutdown(disk.Server.ID)
 		if err != nil {
 			return fmt.Errorf("Error stopping SakuraCloud Server resource: %s", err)
@@ -269,7 +322,35 @@ func resourceSakuraCloudDiskUpdate(d *schema.ResourceData, meta interface{}) err
 		}
 	}
 
-	if isDiskConfigChanged {
+	if isRestore {
+		// the backup already contains the disk edit settings, so they are not applied again
+		err := restoreDiskFromBackup(client, d, disk, backupArchive)
+		if err != nil {
+			return err
+		}
+
+		disk, err = client.Disk.Read(disk.ID)
+		if err != nil {
+			return fmt.Errorf("Couldn't find SakuraCloud Disk resource: %s", err)
+		}
+		d.Set("last_edit", diskEditFingerprint(d, disk.ReinstallCount))
+	} else if isSourceChanged {
+		err := reinstallDisk(client, d, disk)
+		if err != nil {
+			return err
+		}
+		// settings of disk edit are lost by reinstall, so apply all of them again
+		err = editDisk(client, disk.ID, expandDiskEditValue(client, d))
+		if err != nil {
+			return err
+		}
+
+		disk, err = client.Disk.Read(disk.ID)
+		if err != nil {
+			return fmt.Errorf("Couldn't find SakuraCloud Disk resource: %s", err)
+		}
+		d.Set("last_edit", diskEditFingerprint(d, disk.ReinstallCount))
+	} else if isDiskConfigChanged {
 		diskEditConfig := client.Disk.NewCondig()
 		if d.HasChange("hostname") {
 			if hostName, ok := d.GetOk("hostname"); ok {
@@ -309,19 +390,11 @@ func resourceSakuraCloudDiskUpdate(d *schema.ResourceData, meta interface{}) err
 			}
 		}
 
-		res, err := client.Disk.CanEditDisk(disk.ID)
+		err := editDisk(client, disk.ID, diskEditConfig)
 		if err != nil {
-			return fmt.Errorf("Failed to check CanEditDisk: %s", err)
+			return err
 		}
-		if res {
-			_, err := client.Disk.Config(disk.ID, diskEditConfig)
-			if err != nil {
-				return fmt.Errorf("Error editting SakuraCloud DiskConfig: %s", err)
-			}
-		} else {
-			log.Printf("[WARN] Disk[%d] does not support modify disk", disk.ID)
-		}
-
+		d.Set("last_edit", diskEditFingerprint(d, disk.ReinstallCount))
 	}
 
 	if d.HasChange("name") {
@@ -348,13 +421,19 @@ func resourceSakuraCloudDiskUpdate(d *schema.ResourceData, meta interface{}) err
 
 	d.SetId(disk.GetStrID())
 
-	if isRunning && isDiskConfigChanged {
+	// once switched to reinstall_from_archive_id/reinstall_from_disk_id, source_archive_id/source_disk_id are no longer used
+	if archiveID, diskID := d.Get("reinstall_from_archive_id").(string), d.Get("reinstall_from_disk_id").(string); archiveID != "" || diskID != "" {
+		d.Set("source_archive_id", "")
+		d.Set("source_disk_id", "")
+	}
+
+	if isRunning && (isDiskConfigChanged || isSourceChanged || isRestore) {
 		_, err := client.Server.Boot(disk.Server.ID)
 		if err != nil {
 			return fmt.Errorf("Error booting SakuraCloud Server resource: %s", err)
 		}
 
-		err = client.Server.SleepUntilUp(disk.Server.ID, client.DefaultTimeoutDuration)
+		err = sleepUntilServerUp(client, disk.Server.ID)
 		if err != nil {
 			return fmt.Errorf("Error booting SakuraCloud Server resource: %s", err)
 		}
@@ -410,7 +489,7 @@ func resourceSakuraCloudDiskDelete(d *schema.ResourceData, meta interface{}) err
 		if err != nil {
 			return fmt.Errorf("Error booting Server: %s", err)
 		}
-		err = client.Server.SleepUntilUp(disk.Server.ID, client.DefaultTimeoutDuration)
+		err = sleepUntilServerUp(client, disk.Server.ID)
 		if err != nil {
 			return fmt.Errorf("Error booting Server: %s", err)
 		}
@@ -443,7 +522,135 @@ func setDiskResourceData(d *schema.ResourceData, client *api.Client, data *saclo
 		d.Set("server_id", data.Server.GetStrID())
 	}
 
+	if data.Storage.Resource != nil {
+		d.Set("storage_id", data.Storage.GetStrID())
+	}
+	d.Set("storage_class", data.Storage.Class)
+
 	d.Set("zone", client.Zone)
 	d.SetId(data.GetStrID())
 	return nil
 }
+
+// reinstallDisk reinstalls the disk from current reinstall_from_archive_id/reinstall_from_disk_id, keeping its ID
+func reinstallDisk(client *api.Client, d *schema.ResourceData, disk *sacloud.Disk) error {
+	distantFrom := expandDiskDistantFrom(d)
+
+	var err error
+	if archiveID, ok := d.GetOk("reinstall_from_archive_id"); ok {
+		_, err = client.Disk.ReinstallFromArchive(disk.ID, toSakuraCloudID(archiveID.(string)), distantFrom...)
+	} else if diskID, ok := d.GetOk("reinstall_from_disk_id"); ok {
+		_, err = client.Disk.ReinstallFromDisk(disk.ID, toSakuraCloudID(diskID.(string)), distantFrom...)
+	} else {
+		_, err = client.Disk.ReinstallFromBlank(disk.ID, disk.SizeMB)
+	}
+	if err != nil {
+		return fmt.Errorf("Error reinstalling SakuraCloud Disk resource: %s", err)
+	}
+
+	err = client.Disk.SleepWhileCopying(disk.ID, client.DefaultTimeoutDuration)
+	if err != nil {
+		return fmt.Errorf("Error reinstalling SakuraCloud Disk resource: %s", err)
+	}
+	return nil
+}
+
+func restoreDiskFromBackup(client *api.Client, d *schema.ResourceData, disk *sacloud.Disk, archive *sacloud.Archive) error {
+	_, err := client.Disk.ReinstallFromArchive(disk.ID, archive.ID, expandDiskDistantFrom(d)...)
+	if err != nil {
+		return fmt.Errorf("Error restoring SakuraCloud Disk resource from Archive(id:%d): %s", archive.ID, err)
+	}
+
+	err = client.Disk.SleepWhileCopying(disk.ID, client.DefaultTimeoutDuration)
+	if err != nil {
+		return fmt.Errorf("Error restoring SakuraCloud Disk resource from Archive(id:%d): %s", archive.ID, err)
+	}
+	return nil
+}
+
+// expandDiskEditValue returns all settings of disk edit
+func expandDiskEditValue(client *api.Client, d *schema.ResourceData) *sacloud.DiskEditValue {
+	diskEditConfig := client.Disk.NewCondig()
+	if hostName, ok := d.GetOk("hostname"); ok {
+		diskEditConfig.SetHostName(hostName.(string))
+	}
+	if password, ok := d.GetOk("password"); ok {
+		diskEditConfig.SetPassword(password.(string))
+	}
+	if sshKeyIDs, ok := d.GetOk("ssh_key_ids"); ok {
+		ids := expandStringList(sshKeyIDs.([]interface{}))
+		diskEditConfig.SetSSHKeys(ids)
+	}
+
+	if disablePasswordAuth, ok := d.GetOk("disable_pw_auth"); ok {
+		diskEditConfig.SetDisablePWAuth(disablePasswordAuth.(bool))
+	}
+
+	if noteIDs, ok := d.GetOk("note_ids"); ok {
+		ids := expandStringList(noteIDs.([]interface{}))
+		diskEditConfig.SetNotes(ids)
+	}
+	return diskEditConfig
+}
+
+// editDisk calls disk edit API if the disk supports it
+func editDisk(client *api.Client, diskID int64, diskEditConfig *sacloud.DiskEditValue) error {
+	res, err := client.Disk.CanEditDisk(diskID)
+	if err != nil {
+		return fmt.Errorf("Failed to check CanEditDisk: %s", err)
+	}
+	if res {
+		_, err = client.Disk.Config(diskID, diskEditConfig)
+		if err != nil {
+			return fmt.Errorf("Error editting SakuraCloud DiskConfig: %s", err)
+		}
+	} else {
+		log.Printf("[WARN] Disk[%d] does not support modify disk", diskID)
+	}
+	return nil
+}
+
+// diskEditFingerprint returns the fingerprint of disk edit settings applied to the disk.
+// It contains the reinstall count of the disk to detect reinstall outside of terraform.
+func diskEditFingerprint(d *schema.ResourceData, reinstallCount int) string {
+	var buf bytes.Buffer
+	buf.WriteString(fmt.Sprintf("%s-", d.Get("hostname").(string)))
+	buf.WriteString(fmt.Sprintf("%s-", d.Get("password").(string)))
+	buf.WriteString(fmt.Sprintf("%s-", strings.Join(expandStringList(d.Get("ssh_key_ids").([]interface{})), ",")))
+	buf.WriteString(fmt.Sprintf("%t-", d.Get("disable_pw_auth").(bool)))
+	buf.WriteString(fmt.Sprintf("%s-", strings.Join(expandStringList(d.Get("note_ids").([]interface{})), ",")))
+	return fmt.Sprintf("%d-%d", reinstallCount, hashcode.String(buf.String()))
+}
+
+// expandDiskSource returns the source archive ID and the source disk ID to create the disk from
+func expandDiskSource(d *schema.ResourceData) (string, string) {
+	archiveID := d.Get("source_archive_id").(string)
+	if v, ok := d.GetOk("reinstall_from_archive_id"); ok {
+		archiveID = v.(string)
+	}
+	diskID := d.Get("source_disk_id").(string)
+	if v, ok := d.GetOk("reinstall_from_disk_id"); ok {
+		diskID = v.(string)
+	}
+	return archiveID, diskID
+}
+
+// suppressDiskSourceDiff ignores removing source_archive_id/source_disk_id when reinstall_from_archive_id/reinstall_from_disk_id is set,
+// so that the existing disk can switch to in-place reinstall without being recreated.
+func suppressDiskSourceDiff(k, old, new string, d *schema.ResourceData) bool {
+	if new != "" {
+		return false
+	}
+	_, hasArchive := d.GetOk("reinstall_from_archive_id")
+	_, hasDisk := d.GetOk("reinstall_from_disk_id")
+	return hasArchive || hasDisk
+}
+
+// expandDiskDistantFrom returns IDs of disks which should be placed on a different storage
+func expandDiskDistantFrom(d *schema.ResourceData) []int64 {
+	ids := []int64{}
+	for _, id := range expandStringList(d.Get("distant_from").([]interface{})) {
+		ids = append(ids, toSakuraCloudID(id))
+	}
+	return ids
+}
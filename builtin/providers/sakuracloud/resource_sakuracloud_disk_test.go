@@ -7,6 +7,7 @@ import (
 	"github.com/hashicorp/terraform/terraform"
 	"github.com/sacloud/libsacloud/api"
 	"github.com/sacloud/libsacloud/sacloud"
+	"regexp"
 	"testing"
 )
 
@@ -49,12 +50,213 @@ func TestAccResourceSakuraCloudDisk(t *testing.T) {
 						"sakuracloud_disk.foobar", "size", "20"),
 					resource.TestCheckResourceAttr(
 						"sakuracloud_disk.foobar", "disable_pw_auth", "true"),
+					resource.TestCheckResourceAttrSet(
+						"sakuracloud_disk.foobar", "last_edit"),
 				),
 			},
 		},
 	})
 }
 
+func TestAccResourceSakuraCloudDisk_Edit(t *testing.T) {
+	var disk sacloud.Disk
+	var lastEdit string
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckSakuraCloudDiskDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckSakuraCloudDiskConfig_edit,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckSakuraCloudDiskExists("sakuracloud_disk.foobar", &disk),
+					resource.TestCheckResourceAttr(
+						"sakuracloud_disk.foobar", "hostname", "before"),
+					testAccCheckSakuraCloudDiskLastEdit("sakuracloud_disk.foobar", &lastEdit),
+				),
+			},
+			{
+				Config: testAccCheckSakuraCloudDiskConfig_editUpdate,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckSakuraCloudDiskNotRecreated("sakuracloud_disk.foobar", &disk),
+					resource.TestCheckResourceAttr(
+						"sakuracloud_disk.foobar", "hostname", "after"),
+					testAccCheckSakuraCloudDiskLastEdit("sakuracloud_disk.foobar", &lastEdit),
+				),
+			},
+			{
+				// reinstall outside of terraform, then the settings of disk edit should be shown as changes
+				PreConfig: func() {
+					client := testAccProvider.Meta().(*api.Client)
+					if _, err := client.Disk.ReinstallFromArchive(disk.ID, disk.SourceArchive.ID); err != nil {
+						t.Fatalf("Failed to reinstall disk: %s", err)
+					}
+					if err := client.Disk.SleepWhileCopying(disk.ID, client.DefaultTimeoutDuration); err != nil {
+						t.Fatalf("Failed to reinstall disk: %s", err)
+					}
+				},
+				Config:             testAccCheckSakuraCloudDiskConfig_editUpdate,
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: testAccCheckSakuraCloudDiskConfig_editUpdate,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckSakuraCloudDiskNotRecreated("sakuracloud_disk.foobar", &disk),
+					resource.TestCheckResourceAttr(
+						"sakuracloud_disk.foobar", "hostname", "after"),
+					testAccCheckSakuraCloudDiskLastEdit("sakuracloud_disk.foobar", &lastEdit),
+				),
+			},
+		},
+	})
+}
+
+func TestAccResourceSakuraCloudDisk_DistantFrom(t *testing.T) {
+	var disk1, disk2 sacloud.Disk
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckSakuraCloudDiskDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckSakuraCloudDiskConfig_distantFrom,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckSakuraCloudDiskExists("sakuracloud_disk.foobar1", &disk1),
+					testAccCheckSakuraCloudDiskExists("sakuracloud_disk.foobar2", &disk2),
+					resource.TestCheckResourceAttr(
+						"sakuracloud_disk.foobar2", "distant_from.#", "1"),
+					resource.TestCheckResourceAttrSet(
+						"sakuracloud_disk.foobar2", "storage_id"),
+					resource.TestCheckResourceAttrSet(
+						"sakuracloud_disk.foobar2", "storage_class"),
+					func(s *terraform.State) error {
+						if disk1.Storage.ID == disk2.Storage.ID {
+							return fmt.Errorf("Disks are placed on same storage: %d", disk1.Storage.ID)
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func TestAccResourceSakuraCloudDisk_Reinstall(t *testing.T) {
+	var disk sacloud.Disk
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckSakuraCloudDiskDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckSakuraCloudDiskConfig_reinstall,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckSakuraCloudDiskExists("sakuracloud_disk.foobar", &disk),
+					resource.TestCheckResourceAttrPair(
+						"sakuracloud_disk.foobar", "source_archive_id",
+						"data.sakuracloud_archive.ubuntu", "id"),
+				),
+			},
+			{
+				// switching to reinstall_from_archive_id with the same archive keeps the disk
+				Config: testAccCheckSakuraCloudDiskConfig_reinstallSwitch,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckSakuraCloudDiskNotRecreated("sakuracloud_disk.foobar", &disk),
+					resource.TestCheckResourceAttr(
+						"sakuracloud_disk.foobar", "source_archive_id", ""),
+					resource.TestCheckResourceAttrPair(
+						"sakuracloud_disk.foobar", "reinstall_from_archive_id",
+						"data.sakuracloud_archive.ubuntu", "id"),
+				),
+			},
+			{
+				Config: testAccCheckSakuraCloudDiskConfig_reinstallUpdate,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckSakuraCloudDiskNotRecreated("sakuracloud_disk.foobar", &disk),
+					resource.TestCheckResourceAttrPair(
+						"sakuracloud_disk.foobar", "reinstall_from_archive_id",
+						"data.sakuracloud_archive.centos", "id"),
+					resource.TestCheckResourceAttr(
+						"sakuracloud_disk.foobar", "hostname", "reinstalled"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccResourceSakuraCloudDisk_RestoreFromBackup(t *testing.T) {
+	var disk sacloud.Disk
+	var manualArchive *sacloud.Archive
+	defer func() {
+		if manualArchive != nil {
+			client := testAccProvider.Meta().(*api.Client)
+			client.Archive.Delete(manualArchive.ID)
+		}
+	}()
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckSakuraCloudDiskDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckSakuraCloudDiskConfig_restoreFromBackup,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckSakuraCloudDiskExists("sakuracloud_disk.foobar", &disk),
+				),
+			},
+			{
+				// archives created manually from the disk must not be used as backups
+				PreConfig: func() {
+					client := testAccProvider.Meta().(*api.Client)
+					archive := client.Archive.New()
+					archive.Name = "manual_archive"
+					archive.SetSourceDisk(disk.ID)
+					created, err := client.Archive.Create(archive)
+					if err != nil {
+						t.Fatalf("Failed to create archive: %s", err)
+					}
+					manualArchive = created
+					if err := client.Archive.SleepWhileCopying(created.ID, client.DefaultTimeoutDuration); err != nil {
+						t.Fatalf("Failed to create archive: %s", err)
+					}
+				},
+				Config:      testAccCheckSakuraCloudDiskConfig_restoreFromBackupLatest,
+				ExpectError: regexp.MustCompile("Couldn't find backup generation"),
+			},
+		},
+	})
+}
+
+func testAccCheckSakuraCloudDiskNotRecreated(n string, disk *sacloud.Disk) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		if disk.ID == 0 {
+			return errors.New("Disk doesn't have ID")
+		}
+		return resource.TestCheckResourceAttr(n, "id", disk.GetStrID())(s)
+	}
+}
+
+// testAccCheckSakuraCloudDiskLastEdit checks whether last_edit is changed from the previous value, and saves current value
+func testAccCheckSakuraCloudDiskLastEdit(n string, lastEdit *string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+		current := rs.Primary.Attributes["last_edit"]
+		if current == "" {
+			return errors.New("last_edit is empty")
+		}
+		if current == *lastEdit {
+			return fmt.Errorf("last_edit is not changed: %s", current)
+		}
+		*lastEdit = current
+		return nil
+	}
+}
+
 func testAccCheckSakuraCloudDiskExists(n string, disk *sacloud.Disk) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		rs, ok := s.RootModule().Resources[n]
@@ -152,3 +354,98 @@ resource "sakuracloud_disk" "foobar" {
     disable_pw_auth = true
     hostname = "aaaa"
 }`
+
+var testAccCheckSakuraCloudDiskConfig_edit = `
+data "sakuracloud_archive" "ubuntu" {
+    os_type = "ubuntu"
+}
+resource "sakuracloud_disk" "foobar" {
+    name = "mydisk"
+    source_archive_id = "${data.sakuracloud_archive.ubuntu.id}"
+    hostname = "before"
+    password = "DiskPasswordBefore397"
+}`
+
+var testAccCheckSakuraCloudDiskConfig_editUpdate = `
+data "sakuracloud_archive" "ubuntu" {
+    os_type = "ubuntu"
+}
+resource "sakuracloud_disk" "foobar" {
+    name = "mydisk"
+    source_archive_id = "${data.sakuracloud_archive.ubuntu.id}"
+    hostname = "after"
+    password = "DiskPasswordAfter397"
+}`
+
+var testAccCheckSakuraCloudDiskConfig_distantFrom = `
+resource "sakuracloud_disk" "foobar1" {
+    name = "mydisk1"
+}
+resource "sakuracloud_disk" "foobar2" {
+    name = "mydisk2"
+    distant_from = ["${sakuracloud_disk.foobar1.id}"]
+}`
+
+var testAccCheckSakuraCloudDiskConfig_reinstall = `
+data "sakuracloud_archive" "ubuntu" {
+    os_type = "ubuntu"
+}
+data "sakuracloud_archive" "centos" {
+    os_type = "centos"
+}
+resource "sakuracloud_disk" "foobar" {
+    name = "mydisk"
+    source_archive_id = "${data.sakuracloud_archive.ubuntu.id}"
+    hostname = "installed"
+}`
+
+var testAccCheckSakuraCloudDiskConfig_reinstallSwitch = `
+data "sakuracloud_archive" "ubuntu" {
+    os_type = "ubuntu"
+}
+data "sakuracloud_archive" "centos" {
+    os_type = "centos"
+}
+resource "sakuracloud_disk" "foobar" {
+    name = "mydisk"
+    reinstall_from_archive_id = "${data.sakuracloud_archive.ubuntu.id}"
+    hostname = "installed"
+}`
+
+var testAccCheckSakuraCloudDiskConfig_reinstallUpdate = `
+data "sakuracloud_archive" "ubuntu" {
+    os_type = "ubuntu"
+}
+data "sakuracloud_archive" "centos" {
+    os_type = "centos"
+}
+resource "sakuracloud_disk" "foobar" {
+    name = "mydisk"
+    reinstall_from_archive_id = "${data.sakuracloud_archive.centos.id}"
+    hostname = "reinstalled"
+}`
+
+var testAccCheckSakuraCloudDiskConfig_restoreFromBackup = `
+resource "sakuracloud_disk" "foobar" {
+    name = "mydisk"
+    zone = "is1b"
+}
+resource "sakuracloud_auto_backup" "foobar" {
+    name = "mybackup"
+    disk_id = "${sakuracloud_disk.foobar.id}"
+    weekdays = ["mon"]
+    zone = "is1b"
+}`
+
+var testAccCheckSakuraCloudDiskConfig_restoreFromBackupLatest = `
+resource "sakuracloud_disk" "foobar" {
+    name = "mydisk"
+    zone = "is1b"
+    restore_from_backup = "latest"
+}
+resource "sakuracloud_auto_backup" "foobar" {
+    name = "mybackup"
+    disk_id = "${sakuracloud_disk.foobar.id}"
+    weekdays = ["mon"]
+    zone = "is1b"
+}`
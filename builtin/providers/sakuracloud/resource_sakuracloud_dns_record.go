@@ -8,6 +8,8 @@ import (
 	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/sacloud/libsacloud/api"
 	"github.com/sacloud/libsacloud/sacloud"
+	"log"
+	"strconv"
 	"strings"
 )
 
@@ -15,6 +17,7 @@ func resourceSakuraCloudDNSRecord() *schema.Resource {
 	return &schema.Resource{
 		Create: resourceSakuraCloudDNSRecordCreate,
 		Read:   resourceSakuraCloudDNSRecordRead,
+		Update: resourceSakuraCloudDNSRecordUpdate,
 		Delete: resourceSakuraCloudDNSRecordDelete,
 
 		Schema: map[string]*schema.Schema{
@@ -33,7 +36,7 @@ func resourceSakuraCloudDNSRecord() *schema.Resource {
 				Type:         schema.TypeString,
 				Required:     true,
 				ForceNew:     true,
-				ValidateFunc: validateStringInWord(sacloud.AllowDNSTypes()),
+				ValidateFunc: validateStringInWord(allowDNSTypes()),
 			},
 
 			"value": {
@@ -43,10 +46,10 @@ func resourceSakuraCloudDNSRecord() *schema.Resource {
 			},
 
 			"ttl": {
-				Type:     schema.TypeInt,
-				Optional: true,
-				Default:  3600,
-				ForceNew: true,
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      3600,
+				ValidateFunc: validateIntegerInRange(10, 3600000),
 			},
 
 			"priority": {
@@ -101,8 +104,31 @@ func resourceSakuraCloudDNSRecordCreate(d *schema.ResourceData, meta interface{}
 
 func resourceSakuraCloudDNSRecordRead(d *schema.ResourceData, meta interface{}) error {
 	client := meta.(*api.Client)
+	dnsID := d.Get("dns_id").(string)
 
-	dns, err := client.DNS.Read(toSakuraCloudID(d.Get("dns_id").(string)))
+	dns, err := client.DNS.Read(toSakuraCloudID(dnsID))
+	if err != nil {
+		return fmt.Errorf("Couldn't find SakuraCloud DNS resource: %s", err)
+	}
+
+	record := findRecordMatch(expandDNSRecord(d), &dns.Settings.DNS.ResourceRecordSets)
+	if record == nil {
+		log.Printf("[WARN] SakuraCloud DNSRecord resource is not found on DNS(%s): %s", dnsID, d.Id())
+		d.SetId("")
+		return nil
+	}
+
+	return setDNSRecordResourceData(d, dnsID, record)
+}
+
+func resourceSakuraCloudDNSRecordUpdate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*api.Client)
+	dnsID := d.Get("dns_id").(string)
+
+	sakuraMutexKV.Lock(dnsID)
+	defer sakuraMutexKV.Unlock(dnsID)
+
+	dns, err := client.DNS.Read(toSakuraCloudID(dnsID))
 	if err != nil {
 		return fmt.Errorf("Couldn't find SakuraCloud DNS resource: %s", err)
 	}
@@ -112,26 +138,14 @@ func resourceSakuraCloudDNSRecordRead(d *schema.ResourceData, meta interface{})
 		return fmt.Errorf("Couldn't find SakuraCloud DNSRecord resource: %v", record)
 	}
 
-	d.Set("name", record.Name)
-	d.Set("type", record.Type)
-	d.Set("value", record.RData)
-	d.Set("ttl", record.TTL)
-
-	if record.Type == "MX" {
-		// ex. record.RData = "10 example.com."
-		values := strings.SplitN(record.RData, " ", 2)
-		d.Set("value", values[1])
-		d.Set("priority", values[0])
-	} else if record.Type == "SRV" {
-		values := strings.SplitN(record.RData, " ", 4)
-		d.Set("value", values[3])
-		d.Set("priority", values[0])
-		d.Set("weight", values[1])
-		d.Set("port", values[2])
-
+	// AddRecord overwrites TTL of the record that has same name/type/value
+	dns.AddRecord(record)
+	dns, err = client.DNS.Update(toSakuraCloudID(dnsID), dns)
+	if err != nil {
+		return fmt.Errorf("Failed to update SakuraCloud DNSRecord resource: %s", err)
 	}
 
-	return nil
+	return resourceSakuraCloudDNSRecordRead(d, meta)
 }
 
 func resourceSakuraCloudDNSRecordDelete(d *schema.ResourceData, meta interface{}) error {
@@ -174,21 +188,64 @@ func findRecordMatch(r *sacloud.DNSRecordSet, records *[]sacloud.DNSRecordSet) *
 	}
 	return nil
 }
+
+// isSameDNSRecord compares records by name/type/value(RData).
+// TTL is not a part of record identity because it can be updated in place.
 func isSameDNSRecord(r1 *sacloud.DNSRecordSet, r2 *sacloud.DNSRecordSet) bool {
-	return r1.Name == r2.Name && r1.RData == r2.RData && r1.TTL == r2.TTL && r1.Type == r2.Type
+	return r1.Name == r2.Name && r1.RData == r2.RData && r1.Type == r2.Type
 }
 
-func dnsRecordIDHash(dns_id string, r *sacloud.DNSRecordSet) string {
+func dnsRecordIDHash(dnsID string, r *sacloud.DNSRecordSet) string {
 	var buf bytes.Buffer
-	buf.WriteString(fmt.Sprintf("%s-", dns_id))
+	buf.WriteString(fmt.Sprintf("%s-", dnsID))
 	buf.WriteString(fmt.Sprintf("%s-", r.Type))
 	buf.WriteString(fmt.Sprintf("%s-", r.RData))
-	buf.WriteString(fmt.Sprintf("%d-", r.TTL))
 	buf.WriteString(fmt.Sprintf("%s-", r.Name))
 
 	return fmt.Sprintf("dnsrecord-%d", hashcode.String(buf.String()))
 }
 
+func setDNSRecordResourceData(d *schema.ResourceData, dnsID string, record *sacloud.DNSRecordSet) error {
+	d.Set("name", record.Name)
+	d.Set("type", record.Type)
+	d.Set("value", record.RData)
+	d.Set("ttl", record.TTL)
+
+	switch record.Type {
+	case "MX":
+		// ex. record.RData = "10 example.com."
+		values := strings.SplitN(record.RData, " ", 2)
+		if len(values) != 2 {
+			return fmt.Errorf("Invalid MX record data: %q", record.RData)
+		}
+		priority, _ := strconv.Atoi(values[0])
+		d.Set("value", values[1])
+		d.Set("priority", priority)
+	case "SRV":
+		// ex. record.RData = "1 2 3 example.com."
+		values := strings.SplitN(record.RData, " ", 4)
+		if len(values) != 4 {
+			return fmt.Errorf("Invalid SRV record data: %q", record.RData)
+		}
+		priority, _ := strconv.Atoi(values[0])
+		weight, _ := strconv.Atoi(values[1])
+		port, _ := strconv.Atoi(values[2])
+		d.Set("value", values[3])
+		d.Set("priority", priority)
+		d.Set("weight", weight)
+		d.Set("port", port)
+	}
+
+	d.SetId(dnsRecordIDHash(dnsID, record))
+	return nil
+}
+
+// allowDNSTypes returns record types that DNS appliance supports.
+// sacloud.AllowDNSTypes() doesn't contain CAA and PTR yet.
+func allowDNSTypes() []string {
+	return append(sacloud.AllowDNSTypes(), "CAA", "PTR")
+}
+
 func expandDNSRecord(d *schema.ResourceData) *sacloud.DNSRecordSet {
 	var dns = sacloud.DNS{}
 	t := d.Get("type").(string)
@@ -60,6 +60,41 @@ func TestAccResourceSakuraCloudDNSRecord_Basic(t *testing.T) {
 	})
 }
 
+func TestAccResourceSakuraCloudDNSRecord_UpdateTTL(t *testing.T) {
+	var dns sacloud.DNS
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckSakuraCloudDNSRecordDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckSakuraCloudDNSRecordConfig_ttl,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckSakuraCloudDNSExists("sakuracloud_dns.foobar", &dns),
+					resource.TestCheckResourceAttr(
+						"sakuracloud_dns_record.foobar", "ttl", "3600"),
+					resource.TestCheckResourceAttr(
+						"sakuracloud_dns_record.caa", "type", "CAA"),
+					resource.TestCheckResourceAttr(
+						"sakuracloud_dns_record.caa", "value", "0 issue \"letsencrypt.org\""),
+				),
+			},
+			{
+				Config: testAccCheckSakuraCloudDNSRecordConfig_ttl_update,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckSakuraCloudDNSExists("sakuracloud_dns.foobar", &dns),
+					resource.TestCheckResourceAttr(
+						"sakuracloud_dns_record.foobar", "ttl", "60"),
+					resource.TestCheckResourceAttr(
+						"sakuracloud_dns_record.foobar", "value", "192.168.0.1"),
+					resource.TestCheckResourceAttr(
+						"sakuracloud_dns_record.caa", "ttl", "60"),
+				),
+			},
+		},
+	})
+}
+
 func TestAccResourceSakuraCloudDNSRecord_With_Count(t *testing.T) {
 	var dns sacloud.DNS
 	resource.Test(t, resource.TestCase{
@@ -148,6 +183,50 @@ resource "sakuracloud_dns_record" "foobar" {
     value = "192.168.0.2"
 }`
 
+var testAccCheckSakuraCloudDNSRecordConfig_ttl = `
+resource "sakuracloud_dns" "foobar" {
+    zone = "terraform.io"
+    description = "DNS from TerraForm for SAKURA CLOUD"
+    tags = ["hoge1"]
+}
+
+resource "sakuracloud_dns_record" "foobar" {
+    dns_id = "${sakuracloud_dns.foobar.id}"
+    name = "test1"
+    type = "A"
+    value = "192.168.0.1"
+}
+
+resource "sakuracloud_dns_record" "caa" {
+    dns_id = "${sakuracloud_dns.foobar.id}"
+    name = "@"
+    type = "CAA"
+    value = "0 issue \"letsencrypt.org\""
+}`
+
+var testAccCheckSakuraCloudDNSRecordConfig_ttl_update = `
+resource "sakuracloud_dns" "foobar" {
+    zone = "terraform.io"
+    description = "DNS from TerraForm for SAKURA CLOUD"
+    tags = ["hoge1"]
+}
+
+resource "sakuracloud_dns_record" "foobar" {
+    dns_id = "${sakuracloud_dns.foobar.id}"
+    name = "test1"
+    type = "A"
+    value = "192.168.0.1"
+    ttl = 60
+}
+
+resource "sakuracloud_dns_record" "caa" {
+    dns_id = "${sakuracloud_dns.foobar.id}"
+    name = "@"
+    type = "CAA"
+    value = "0 issue \"letsencrypt.org\""
+    ttl = 60
+}`
+
 var testAccCheckSakuraCloudDNSRecordConfig_with_count = `
 
 resource "sakuracloud_dns" "foobar" {
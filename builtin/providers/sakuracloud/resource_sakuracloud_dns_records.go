@@ -0,0 +1,305 @@
+package sakuracloud
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/sacloud/libsacloud/api"
+	"github.com/sacloud/libsacloud/sacloud"
+)
+
+func resourceSakuraCloudDNSRecords() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceSakuraCloudDNSRecordsCreate,
+		Read:   resourceSakuraCloudDNSRecordsRead,
+		Update: resourceSakuraCloudDNSRecordsUpdate,
+		Delete: resourceSakuraCloudDNSRecordsDelete,
+
+		Schema: map[string]*schema.Schema{
+			"dns_id": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validateSakuracloudIDType,
+			},
+			"records": {
+				Type:          schema.TypeList,
+				Optional:      true,
+				Computed:      true,
+				ConflictsWith: []string{"zone_file"},
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"type": {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validateStringInWord(allowDNSTypes()),
+						},
+						"value": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"ttl": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							Default:      defaultDNSRecordTTL,
+							ValidateFunc: validateIntegerInRange(10, 3600000),
+						},
+					},
+				},
+			},
+			"zone_file": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"records"},
+			},
+			"preserve_unmanaged": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"exported_zone_file": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func resourceSakuraCloudDNSRecordsCreate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*api.Client)
+	dnsID := d.Get("dns_id").(string)
+
+	if err := applyDNSRecords(d, client, []sacloud.DNSRecordSet{}); err != nil {
+		return fmt.Errorf("Failed to create SakuraCloud DNSRecords resource: %s", err)
+	}
+
+	d.SetId(fmt.Sprintf("dnsrecords-%s", dnsID))
+	return resourceSakuraCloudDNSRecordsRead(d, meta)
+}
+
+func resourceSakuraCloudDNSRecordsRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*api.Client)
+
+	dns, err := client.DNS.Read(toSakuraCloudID(d.Get("dns_id").(string)))
+	if err != nil {
+		return fmt.Errorf("Couldn't find SakuraCloud DNS resource: %s", err)
+	}
+	current := dns.Settings.DNS.ResourceRecordSets
+	prev := expandDNSRecordSets(d.Get("records").([]interface{}))
+
+	// when preserve_unmanaged is enabled, only records that are recorded in state are managed by this resource
+	managed := []sacloud.DNSRecordSet{}
+	preserve := d.Get("preserve_unmanaged").(bool)
+	for _, r := range current {
+		if !preserve || findRecordMatch(&r, &prev) != nil {
+			managed = append(managed, r)
+		}
+	}
+	managed = sortDNSRecordSetsByReference(managed, prev)
+
+	if zoneFile, ok := d.GetOk("zone_file"); ok {
+		desired, err := parseDNSZoneFile(dns.Name, zoneFile.(string))
+		if err != nil || !isSameDNSRecordSets(desired, managed) {
+			// show drift as the diff of zone_file
+			d.Set("zone_file", exportDNSZoneFile(dns.Name, managed))
+		}
+	}
+
+	d.Set("records", flattenDNSRecordSets(managed))
+	d.Set("exported_zone_file", exportDNSZoneFile(dns.Name, current))
+	return nil
+}
+
+func resourceSakuraCloudDNSRecordsUpdate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*api.Client)
+
+	o, _ := d.GetChange("records")
+	if err := applyDNSRecords(d, client, expandDNSRecordSets(o.([]interface{}))); err != nil {
+		return fmt.Errorf("Failed to update SakuraCloud DNSRecords resource: %s", err)
+	}
+
+	return resourceSakuraCloudDNSRecordsRead(d, meta)
+}
+
+func resourceSakuraCloudDNSRecordsDelete(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*api.Client)
+	dnsID := d.Get("dns_id").(string)
+
+	sakuraMutexKV.Lock(dnsID)
+	defer sakuraMutexKV.Unlock(dnsID)
+
+	dns, err := client.DNS.Read(toSakuraCloudID(dnsID))
+	if err != nil {
+		return fmt.Errorf("Couldn't find SakuraCloud DNS resource: %s", err)
+	}
+
+	records := dns.Settings.DNS.ResourceRecordSets
+	dns.ClearRecords()
+
+	if d.Get("preserve_unmanaged").(bool) {
+		managed := expandDNSRecordSets(d.Get("records").([]interface{}))
+		for _, r := range records {
+			if findRecordMatch(&r, &managed) == nil {
+				dns.AddRecord(&r)
+			}
+		}
+	}
+
+	_, err = client.DNS.Update(toSakuraCloudID(dnsID), dns)
+	if err != nil {
+		return fmt.Errorf("Failed to delete SakuraCloud DNSRecords resource: %s", err)
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// applyDNSRecords updates records of the DNS zone to desired state with a single DNS.Update call.
+// prevManaged is used to decide which records can be removed when preserve_unmanaged is enabled.
+func applyDNSRecords(d *schema.ResourceData, client *api.Client, prevManaged []sacloud.DNSRecordSet) error {
+	dnsID := d.Get("dns_id").(string)
+
+	sakuraMutexKV.Lock(dnsID)
+	defer sakuraMutexKV.Unlock(dnsID)
+
+	dns, err := client.DNS.Read(toSakuraCloudID(dnsID))
+	if err != nil {
+		return fmt.Errorf("Couldn't find SakuraCloud DNS resource: %s", err)
+	}
+
+	var desired []sacloud.DNSRecordSet
+	if zoneFile, ok := d.GetOk("zone_file"); ok {
+		desired, err = parseDNSZoneFile(dns.Name, zoneFile.(string))
+		if err != nil {
+			return err
+		}
+	} else {
+		desired = expandDNSRecordSets(d.Get("records").([]interface{}))
+	}
+	for i := range desired {
+		if countDNSRecordMatch(&desired[i], desired) > 1 {
+			return fmt.Errorf("Duplicate DNS record: %v", desired[i])
+		}
+	}
+
+	preserve := d.Get("preserve_unmanaged").(bool)
+	removable := func(r *sacloud.DNSRecordSet) bool {
+		return !preserve || findRecordMatch(r, &prevManaged) != nil
+	}
+
+	records, added, removed, updated := diffDNSRecordSets(dns.Settings.DNS.ResourceRecordSets, desired, removable)
+	log.Printf("[INFO] SakuraCloud DNS(%s) records: %d to add, %d to change, %d to destroy", dnsID, added, updated, removed)
+
+	if added+removed+updated > 0 {
+		dns.Settings.DNS.ResourceRecordSets = records
+		_, err = client.DNS.Update(toSakuraCloudID(dnsID), dns)
+		if err != nil {
+			return err
+		}
+	}
+
+	// records applied by this resource are treated as managed on next Read
+	d.Set("records", flattenDNSRecordSets(desired))
+	return nil
+}
+
+// diffDNSRecordSets merges desired records into current records, keeping the order of current records.
+// Records that are not desired are removed only if removable returns true.
+func diffDNSRecordSets(current, desired []sacloud.DNSRecordSet, removable func(*sacloud.DNSRecordSet) bool) (records []sacloud.DNSRecordSet, added, removed, updated int) {
+	records = []sacloud.DNSRecordSet{}
+	for _, c := range current {
+		if r := findRecordMatch(&c, &desired); r != nil {
+			if c.TTL != r.TTL {
+				c.TTL = r.TTL
+				updated++
+			}
+			records = append(records, c)
+			continue
+		}
+		if removable(&c) {
+			removed++
+			continue
+		}
+		records = append(records, c)
+	}
+
+	for _, r := range desired {
+		if findRecordMatch(&r, &current) == nil {
+			records = append(records, r)
+			added++
+		}
+	}
+	return
+}
+
+func countDNSRecordMatch(r *sacloud.DNSRecordSet, records []sacloud.DNSRecordSet) int {
+	count := 0
+	for i := range records {
+		if isSameDNSRecord(r, &records[i]) {
+			count++
+		}
+	}
+	return count
+}
+
+// sortDNSRecordSetsByReference sorts records in the order of ref to keep the order of list in state.
+// Records that are not contained in ref are placed at the end.
+func sortDNSRecordSetsByReference(records, ref []sacloud.DNSRecordSet) []sacloud.DNSRecordSet {
+	sorted := []sacloud.DNSRecordSet{}
+	for _, r := range ref {
+		if match := findRecordMatch(&r, &records); match != nil {
+			sorted = append(sorted, *match)
+		}
+	}
+	for _, r := range records {
+		if findRecordMatch(&r, &sorted) == nil {
+			sorted = append(sorted, r)
+		}
+	}
+	return sorted
+}
+
+func isSameDNSRecordSets(r1, r2 []sacloud.DNSRecordSet) bool {
+	if len(r1) != len(r2) {
+		return false
+	}
+	for _, r := range r1 {
+		match := findRecordMatch(&r, &r2)
+		if match == nil || match.TTL != r.TTL {
+			return false
+		}
+	}
+	return true
+}
+
+func expandDNSRecordSets(configured []interface{}) []sacloud.DNSRecordSet {
+	records := []sacloud.DNSRecordSet{}
+	for _, raw := range configured {
+		v := raw.(map[string]interface{})
+		records = append(records, sacloud.DNSRecordSet{
+			Name:  v["name"].(string),
+			Type:  v["type"].(string),
+			RData: v["value"].(string),
+			TTL:   v["ttl"].(int),
+		})
+	}
+	return records
+}
+
+func flattenDNSRecordSets(records []sacloud.DNSRecordSet) []interface{} {
+	ret := []interface{}{}
+	for _, r := range records {
+		ret = append(ret, map[string]interface{}{
+			"name":  r.Name,
+			"type":  r.Type,
+			"value": r.RData,
+			"ttl":   r.TTL,
+		})
+	}
+	return ret
+}
@@ -0,0 +1,150 @@
+package sakuracloud
+
+import (
+	"fmt"
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/sacloud/libsacloud/sacloud"
+	"testing"
+)
+
+func TestAccResourceSakuraCloudDNSRecords_Basic(t *testing.T) {
+	var dns sacloud.DNS
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckSakuraCloudDNSRecordDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckSakuraCloudDNSRecordsConfig_basic,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckSakuraCloudDNSExists("sakuracloud_dns.foobar", &dns),
+					testAccCheckSakuraCloudDNSRecordsCount(&dns, 2),
+					resource.TestCheckResourceAttr(
+						"sakuracloud_dns_records.foobar", "records.#", "2"),
+					resource.TestCheckResourceAttr(
+						"sakuracloud_dns_records.foobar", "records.0.name", "test1"),
+					resource.TestCheckResourceAttr(
+						"sakuracloud_dns_records.foobar", "records.0.type", "A"),
+					resource.TestCheckResourceAttr(
+						"sakuracloud_dns_records.foobar", "records.0.value", "192.168.0.1"),
+					resource.TestCheckResourceAttr(
+						"sakuracloud_dns_records.foobar", "records.1.type", "MX"),
+					resource.TestCheckResourceAttr(
+						"sakuracloud_dns_records.foobar", "records.1.value", "10 mail.terraform.io."),
+				),
+			},
+			{
+				Config: testAccCheckSakuraCloudDNSRecordsConfig_zoneFile,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckSakuraCloudDNSExists("sakuracloud_dns.foobar", &dns),
+					testAccCheckSakuraCloudDNSRecordsCount(&dns, 3),
+					resource.TestCheckResourceAttr(
+						"sakuracloud_dns_records.foobar", "records.#", "3"),
+					resource.TestCheckResourceAttr(
+						"sakuracloud_dns_records.foobar", "records.0.name", "test1"),
+					resource.TestCheckResourceAttr(
+						"sakuracloud_dns_records.foobar", "records.0.ttl", "60"),
+					resource.TestCheckResourceAttr(
+						"sakuracloud_dns_records.foobar", "records.2.name", "www"),
+					resource.TestCheckResourceAttrSet(
+						"sakuracloud_dns_records.foobar", "exported_zone_file"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccResourceSakuraCloudDNSRecords_PreserveUnmanaged(t *testing.T) {
+	var dns sacloud.DNS
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckSakuraCloudDNSRecordDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckSakuraCloudDNSRecordsConfig_preserve,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckSakuraCloudDNSExists("sakuracloud_dns.foobar", &dns),
+					testAccCheckSakuraCloudDNSRecordsCount(&dns, 2),
+					resource.TestCheckResourceAttr(
+						"sakuracloud_dns_records.foobar", "records.#", "1"),
+					resource.TestCheckResourceAttr(
+						"sakuracloud_dns_records.foobar", "records.0.name", "test2"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckSakuraCloudDNSRecordsCount(dns *sacloud.DNS, count int) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		if len(dns.Settings.DNS.ResourceRecordSets) != count {
+			return fmt.Errorf("Bad DNS records count: expected %d, got %d", count, len(dns.Settings.DNS.ResourceRecordSets))
+		}
+		return nil
+	}
+}
+
+var testAccCheckSakuraCloudDNSRecordsConfig_basic = `
+resource "sakuracloud_dns" "foobar" {
+    zone = "terraform.io"
+    description = "DNS from TerraForm for SAKURA CLOUD"
+    tags = ["hoge1"]
+}
+
+resource "sakuracloud_dns_records" "foobar" {
+    dns_id = "${sakuracloud_dns.foobar.id}"
+    records {
+        name = "test1"
+        type = "A"
+        value = "192.168.0.1"
+    }
+    records {
+        name = "@"
+        type = "MX"
+        value = "10 mail.terraform.io."
+    }
+}`
+
+var testAccCheckSakuraCloudDNSRecordsConfig_zoneFile = `
+resource "sakuracloud_dns" "foobar" {
+    zone = "terraform.io"
+    description = "DNS from TerraForm for SAKURA CLOUD"
+    tags = ["hoge1"]
+}
+
+resource "sakuracloud_dns_records" "foobar" {
+    dns_id = "${sakuracloud_dns.foobar.id}"
+    zone_file = <<EOT
+$ORIGIN terraform.io.
+$TTL 3600
+test1   60  IN A   192.168.0.1
+@           IN MX  10 mail.terraform.io.
+www         IN CNAME test1.terraform.io.
+EOT
+}`
+
+var testAccCheckSakuraCloudDNSRecordsConfig_preserve = `
+resource "sakuracloud_dns" "foobar" {
+    zone = "terraform.io"
+    description = "DNS from TerraForm for SAKURA CLOUD"
+    tags = ["hoge1"]
+}
+
+resource "sakuracloud_dns_record" "foobar" {
+    dns_id = "${sakuracloud_dns.foobar.id}"
+    name = "test1"
+    type = "A"
+    value = "192.168.0.1"
+}
+
+resource "sakuracloud_dns_records" "foobar" {
+    dns_id = "${sakuracloud_dns_record.foobar.dns_id}"
+    preserve_unmanaged = true
+    records {
+        name = "test2"
+        type = "A"
+        value = "192.168.0.2"
+    }
+}`
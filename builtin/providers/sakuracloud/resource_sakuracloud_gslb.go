@@ -3,12 +3,16 @@ package sakuracloud
 import (
 	"fmt"
 
-	"github.com/hashicorp/terraform/helper/hashcode"
 	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/sacloud/libsacloud/api"
 	"github.com/sacloud/libsacloud/sacloud"
+	"strconv"
+	"strings"
 )
 
+// gslbMaxServerCount is the maximum number of servers which the GSLB appliance can have
+const gslbMaxServerCount = 6
+
 func resourceSakuraCloudGSLB() *schema.Resource {
 	return &schema.Resource{
 		Create: resourceSakuraCloudGSLBCreate,
@@ -18,6 +22,8 @@ func resourceSakuraCloudGSLB() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
+		SchemaVersion: 1,
+		MigrateState:  migrateStateFunc("sakuracloud_gslb", gslbStateMigrations...),
 
 		Schema: map[string]*schema.Schema{
 			"name": {
@@ -30,8 +36,9 @@ func resourceSakuraCloudGSLB() *schema.Resource {
 				Computed: true,
 			},
 			"health_check": {
-				Type:     schema.TypeSet,
+				Type:     schema.TypeList,
 				Required: true,
+				MaxItems: 1,
 
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
@@ -74,6 +81,31 @@ func resourceSakuraCloudGSLB() *schema.Resource {
 				Type:     schema.TypeString,
 				Optional: true,
 			},
+			"servers": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Computed: true,
+				MaxItems: gslbMaxServerCount,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"ipaddress": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"enabled": {
+							Type:     schema.TypeBool,
+							Optional: true,
+							Default:  true,
+						},
+						"weight": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							ValidateFunc: validateIntegerInRange(1, 10000),
+							Default:      1,
+						},
+					},
+				},
+			},
 			"description": {
 				Type:     schema.TypeString,
 				Optional: true,
@@ -92,8 +124,7 @@ func resourceSakuraCloudGSLBCreate(d *schema.ResourceData, meta interface{}) err
 
 	opts := client.GSLB.New(d.Get("name").(string))
 
-	healthCheckConf := d.Get("health_check").(*schema.Set)
-	for _, c := range healthCheckConf.List() {
+	for _, c := range d.Get("health_check").([]interface{}) {
 		conf := c.(map[string]interface{})
 		protocol := conf["protocol"].(string)
 		switch protocol {
@@ -127,6 +158,14 @@ func resourceSakuraCloudGSLBCreate(d *schema.ResourceData, meta interface{}) err
 		opts.Settings.GSLB.SorryServer = sorryServer.(string)
 	}
 
+	if rawServers, ok := d.GetOk("servers"); ok {
+		servers, err := expandGSLBServers(rawServers.([]interface{}))
+		if err != nil {
+			return fmt.Errorf("Failed to create SakuraCloud GSLB resource: %s", err)
+		}
+		opts.Settings.GSLB.Servers = servers
+	}
+
 	if description, ok := d.GetOk("description"); ok {
 		opts.Description = description.(string)
 	}
@@ -159,14 +198,16 @@ func resourceSakuraCloudGSLBUpdate(d *schema.ResourceData, meta interface{}) err
 
 	client := meta.(*api.Client)
 
+	sakuraMutexKV.Lock(d.Id())
+	defer sakuraMutexKV.Unlock(d.Id())
+
 	gslb, err := client.GSLB.Read(toSakuraCloudID(d.Id()))
 	if err != nil {
 		return fmt.Errorf("Couldn't find SakuraCloud GSLB resource: %s", err)
 	}
 
 	if d.HasChange("health_check") {
-		healthCheckConf := d.Get("health_check").(*schema.Set)
-		for _, c := range healthCheckConf.List() {
+		for _, c := range d.Get("health_check").([]interface{}) {
 			conf := c.(map[string]interface{})
 			protocol := conf["protocol"].(string)
 			switch protocol {
@@ -207,6 +248,14 @@ func resourceSakuraCloudGSLBUpdate(d *schema.ResourceData, meta interface{}) err
 		}
 	}
 
+	if d.HasChange("servers") {
+		servers, err := expandGSLBServers(d.Get("servers").([]interface{}))
+		if err != nil {
+			return fmt.Errorf("Failed to update SakuraCloud GSLB resource: %s", err)
+		}
+		gslb.Settings.GSLB.Servers = servers
+	}
+
 	if d.HasChange("description") {
 		if description, ok := d.GetOk("description"); ok {
 			gslb.Description = description.(string)
@@ -241,28 +290,9 @@ func resourceSakuraCloudGSLBDelete(d *schema.ResourceData, meta interface{}) err
 	return nil
 }
 
-func healthCheckHash(v interface{}) int {
-	target := v.(map[string]interface{})
-
-	protocol := target["protocol"].(string)
-	host_header := ""
-	path := ""
-	status := ""
-	port := ""
-
-	switch protocol {
-	case "http", "https":
-		host_header = target["host_header"].(string)
-		path = target["path"].(string)
-		status = target["status"].(string)
-	case "tcp":
-		port = target["port"].(string)
-	}
-
-	delay_loop := target["delay_loop"].(int)
-
-	hk := fmt.Sprintf("%s:%d:%s:%s:%s:%s", protocol, delay_loop, host_header, path, status, port)
-	return hashcode.String(hk)
+var gslbStateMigrations = []stateMigrateFunc{
+	// v0 => v1: health_check is changed from set to list
+	setToListStateAttribute("health_check"),
 }
 
 func setGSLBResourceData(d *schema.ResourceData, _ *api.Client, data *sacloud.GSLB) error {
@@ -282,9 +312,10 @@ func setGSLBResourceData(d *schema.ResourceData, _ *api.Client, data *sacloud.GS
 	}
 	healthCheck["protocol"] = data.Settings.GSLB.HealthCheck.Protocol
 	healthCheck["delay_loop"] = data.Settings.GSLB.DelayLoop
-	d.Set("health_check", schema.NewSet(healthCheckHash, []interface{}{healthCheck}))
+	d.Set("health_check", []interface{}{healthCheck})
 
 	d.Set("sorry_server", data.Settings.GSLB.SorryServer)
+	d.Set("servers", flattenGSLBServers(data.Settings.GSLB.Servers))
 	d.Set("description", data.Description)
 	d.Set("tags", data.Tags)
 	d.Set("weighted", data.Settings.GSLB.Weighted == "True")
@@ -292,3 +323,36 @@ func setGSLBResourceData(d *schema.ResourceData, _ *api.Client, data *sacloud.GS
 	d.SetId(data.GetStrID())
 	return nil
 }
+
+func expandGSLBServers(configured []interface{}) ([]sacloud.GSLBServer, error) {
+	servers := []sacloud.GSLBServer{}
+	for _, raw := range configured {
+		v := raw.(map[string]interface{})
+		server := sacloud.GSLBServer{
+			IPAddress: v["ipaddress"].(string),
+			Enabled:   "True",
+			Weight:    fmt.Sprintf("%d", v["weight"].(int)),
+		}
+		if !v["enabled"].(bool) {
+			server.Enabled = "False"
+		}
+		if r := findGSLBServerMatch(&server, &servers); r != nil {
+			return nil, fmt.Errorf("Duplicate GSLB server: %s", server.IPAddress)
+		}
+		servers = append(servers, server)
+	}
+	return servers, nil
+}
+
+func flattenGSLBServers(servers []sacloud.GSLBServer) []interface{} {
+	ret := []interface{}{}
+	for _, s := range servers {
+		weight, _ := strconv.Atoi(s.Weight)
+		ret = append(ret, map[string]interface{}{
+			"ipaddress": s.IPAddress,
+			"enabled":   strings.ToLower(s.Enabled) == "true",
+			"weight":    weight,
+		})
+	}
+	return ret
+}
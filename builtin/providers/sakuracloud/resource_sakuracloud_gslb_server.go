@@ -8,12 +8,16 @@ import (
 	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/sacloud/libsacloud/api"
 	"github.com/sacloud/libsacloud/sacloud"
+	"log"
+	"strconv"
+	"strings"
 )
 
 func resourceSakuraCloudGSLBServer() *schema.Resource {
 	return &schema.Resource{
 		Create: resourceSakuraCloudGSLBServerCreate,
 		Read:   resourceSakuraCloudGSLBServerRead,
+		Update: resourceSakuraCloudGSLBServerUpdate,
 		Delete: resourceSakuraCloudGSLBServerDelete,
 
 		Schema: map[string]*schema.Schema{
@@ -31,14 +35,12 @@ func resourceSakuraCloudGSLBServer() *schema.Resource {
 			"enabled": {
 				Type:     schema.TypeBool,
 				Optional: true,
-				ForceNew: true,
 				Default:  true,
 			},
 			"weight": {
 				Type:         schema.TypeInt,
 				Optional:     true,
 				ValidateFunc: validateIntegerInRange(1, 10000),
-				ForceNew:     true,
 				Default:      1,
 			},
 		},
@@ -62,6 +64,9 @@ func resourceSakuraCloudGSLBServerCreate(d *schema.ResourceData, meta interface{
 	if r := findGSLBServerMatch(server, &gslb.Settings.GSLB.Servers); r != nil {
 		return fmt.Errorf("Failed to create SakuraCloud GSLB resource:Duplicate GSLB server: %v", server)
 	}
+	if len(gslb.Settings.GSLB.Servers) >= gslbMaxServerCount {
+		return fmt.Errorf("Failed to create SakuraCloud GSLBServer resource: GSLB can have up to %d servers", gslbMaxServerCount)
+	}
 
 	gslb.AddGSLBServer(server)
 	gslb, err = client.GSLB.Update(toSakuraCloudID(gslbID), gslb)
@@ -75,8 +80,37 @@ func resourceSakuraCloudGSLBServerCreate(d *schema.ResourceData, meta interface{
 
 func resourceSakuraCloudGSLBServerRead(d *schema.ResourceData, meta interface{}) error {
 	client := meta.(*api.Client)
+	gslbID := d.Get("gslb_id").(string)
 
-	gslb, err := client.GSLB.Read(toSakuraCloudID(d.Get("gslb_id").(string)))
+	gslb, err := client.GSLB.Read(toSakuraCloudID(gslbID))
+	if err != nil {
+		return fmt.Errorf("Couldn't find SakuraCloud GSLB resource: %s", err)
+	}
+
+	server := findGSLBServerMatch(expandGSLBServer(d), &gslb.Settings.GSLB.Servers)
+	if server == nil {
+		log.Printf("[WARN] SakuraCloud GSLBServer resource is not found on GSLB(%s): %s", gslbID, d.Id())
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("ipaddress", server.IPAddress)
+	d.Set("enabled", strings.ToLower(server.Enabled) == "true")
+	weight, _ := strconv.Atoi(server.Weight)
+	d.Set("weight", weight)
+
+	d.SetId(gslbServerIDHash(gslbID, server))
+	return nil
+}
+
+func resourceSakuraCloudGSLBServerUpdate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*api.Client)
+	gslbID := d.Get("gslb_id").(string)
+
+	sakuraMutexKV.Lock(gslbID)
+	defer sakuraMutexKV.Unlock(gslbID)
+
+	gslb, err := client.GSLB.Read(toSakuraCloudID(gslbID))
 	if err != nil {
 		return fmt.Errorf("Couldn't find SakuraCloud GSLB resource: %s", err)
 	}
@@ -86,11 +120,14 @@ func resourceSakuraCloudGSLBServerRead(d *schema.ResourceData, meta interface{})
 		return fmt.Errorf("Couldn't find SakuraCloud GSLBServer resource: %v", server)
 	}
 
-	d.Set("ipaddress", server.IPAddress)
-	d.Set("enabled", server.Enabled)
-	d.Set("weight", server.Weight)
+	// AddGSLBServer overwrites enabled/weight of the server that has same IP address
+	gslb.AddGSLBServer(server)
+	gslb, err = client.GSLB.Update(toSakuraCloudID(gslbID), gslb)
+	if err != nil {
+		return fmt.Errorf("Failed to update SakuraCloud GSLBServer resource: %s", err)
+	}
 
-	return nil
+	return resourceSakuraCloudGSLBServerRead(d, meta)
 }
 
 func resourceSakuraCloudGSLBServerDelete(d *schema.ResourceData, meta interface{}) error {
@@ -134,8 +171,6 @@ func gslbServerIDHash(gslbID string, s *sacloud.GSLBServer) string {
 	var buf bytes.Buffer
 	buf.WriteString(fmt.Sprintf("%s-", gslbID))
 	buf.WriteString(fmt.Sprintf("%s-", s.IPAddress))
-	buf.WriteString(fmt.Sprintf("%s-", s.Weight))
-	buf.WriteString(fmt.Sprintf("%s-", s.Enabled))
 
 	return fmt.Sprintf("gslbserver-%d", hashcode.String(buf.String()))
 }
@@ -40,6 +40,20 @@ func TestAccResourceSakuraCloudGSLBServer(t *testing.T) {
 						"sakuracloud_gslb_server.foobar.3", "ipaddress", "208.67.220.123"),
 				),
 			},
+			{
+				Config: testAccCheckSakuraCloudGSLBServerConfig_weight,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckSakuraCloudGSLBExists("sakuracloud_gslb.foobar", &gslb),
+					resource.TestCheckResourceAttr(
+						"sakuracloud_gslb_server.foobar.0", "weight", "1"),
+					resource.TestCheckResourceAttr(
+						"sakuracloud_gslb_server.foobar.0", "enabled", "true"),
+					resource.TestCheckResourceAttr(
+						"sakuracloud_gslb_server.foobar.1", "weight", "2"),
+					resource.TestCheckResourceAttr(
+						"sakuracloud_gslb_server.foobar.1", "enabled", "false"),
+				),
+			},
 		},
 	})
 }
@@ -106,3 +120,28 @@ resource "sakuracloud_gslb_server" "foobar" {
     ipaddress = "${element(split("," , var.gslb_ip_list),count.index)}"
 
 }`
+
+var testAccCheckSakuraCloudGSLBServerConfig_weight = `
+variable "gslb_ip_list" {
+    default = "8.8.8.8,8.8.4.4"
+}
+resource "sakuracloud_gslb" "foobar" {
+    name = "terraform.io"
+    health_check = {
+        protocol = "https"
+        delay_loop = 20
+        host_header = "update.terraform.io"
+        path = "/"
+        status = "200"
+    }
+    weighted = true
+    description = "GSLB from TerraForm for SAKURA CLOUD"
+    tags = ["hoge1", "hoge2"]
+}
+resource "sakuracloud_gslb_server" "foobar" {
+    count = 2
+    gslb_id = "${sakuracloud_gslb.foobar.id}"
+    ipaddress = "${element(split("," , var.gslb_ip_list),count.index)}"
+    weight = "${count.index + 1}"
+    enabled = "${count.index == 0}"
+}`
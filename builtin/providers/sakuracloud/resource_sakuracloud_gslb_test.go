@@ -26,11 +26,11 @@ func TestAccResourceSakuraCloudGSLB(t *testing.T) {
 					resource.TestCheckResourceAttr(
 						"sakuracloud_gslb.foobar", "sorry_server", "8.8.8.8"),
 					resource.TestCheckResourceAttr(
-						"sakuracloud_gslb.foobar", "health_check.1802742300.protocol", "http"),
+						"sakuracloud_gslb.foobar", "health_check.0.protocol", "http"),
 					resource.TestCheckResourceAttr(
-						"sakuracloud_gslb.foobar", "health_check.1802742300.delay_loop", "10"),
+						"sakuracloud_gslb.foobar", "health_check.0.delay_loop", "10"),
 					resource.TestCheckResourceAttr(
-						"sakuracloud_gslb.foobar", "health_check.1802742300.host_header", "terraform.io"),
+						"sakuracloud_gslb.foobar", "health_check.0.host_header", "terraform.io"),
 				),
 			},
 			{
@@ -42,11 +42,11 @@ func TestAccResourceSakuraCloudGSLB(t *testing.T) {
 					resource.TestCheckResourceAttr(
 						"sakuracloud_gslb.foobar", "sorry_server", "8.8.4.4"),
 					resource.TestCheckResourceAttr(
-						"sakuracloud_gslb.foobar", "health_check.755645870.protocol", "https"),
+						"sakuracloud_gslb.foobar", "health_check.0.protocol", "https"),
 					resource.TestCheckResourceAttr(
-						"sakuracloud_gslb.foobar", "health_check.755645870.delay_loop", "20"),
+						"sakuracloud_gslb.foobar", "health_check.0.delay_loop", "20"),
 					resource.TestCheckResourceAttr(
-						"sakuracloud_gslb.foobar", "health_check.755645870.host_header", "update.terraform.io"),
+						"sakuracloud_gslb.foobar", "health_check.0.host_header", "update.terraform.io"),
 				),
 			},
 			{
@@ -58,11 +58,50 @@ func TestAccResourceSakuraCloudGSLB(t *testing.T) {
 					resource.TestCheckResourceAttr(
 						"sakuracloud_gslb.foobar", "sorry_server", "8.8.4.4"),
 					resource.TestCheckResourceAttr(
-						"sakuracloud_gslb.foobar", "health_check.755645870.protocol", "https"),
+						"sakuracloud_gslb.foobar", "health_check.0.protocol", "https"),
 					resource.TestCheckResourceAttr(
-						"sakuracloud_gslb.foobar", "health_check.755645870.delay_loop", "20"),
+						"sakuracloud_gslb.foobar", "health_check.0.delay_loop", "20"),
 					resource.TestCheckResourceAttr(
-						"sakuracloud_gslb.foobar", "health_check.755645870.host_header", "update.terraform.io"),
+						"sakuracloud_gslb.foobar", "health_check.0.host_header", "update.terraform.io"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccResourceSakuraCloudGSLB_WithServers(t *testing.T) {
+	var gslb sacloud.GSLB
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckSakuraCloudGSLBDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckSakuraCloudGSLBConfig_withServers,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckSakuraCloudGSLBExists("sakuracloud_gslb.foobar", &gslb),
+					resource.TestCheckResourceAttr(
+						"sakuracloud_gslb.foobar", "servers.#", "2"),
+					resource.TestCheckResourceAttr(
+						"sakuracloud_gslb.foobar", "servers.0.ipaddress", "8.8.8.8"),
+					resource.TestCheckResourceAttr(
+						"sakuracloud_gslb.foobar", "servers.0.weight", "1"),
+					resource.TestCheckResourceAttr(
+						"sakuracloud_gslb.foobar", "servers.1.ipaddress", "8.8.4.4"),
+					resource.TestCheckResourceAttr(
+						"sakuracloud_gslb.foobar", "servers.1.enabled", "true"),
+				),
+			},
+			{
+				Config: testAccCheckSakuraCloudGSLBConfig_withServersUpdate,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckSakuraCloudGSLBExists("sakuracloud_gslb.foobar", &gslb),
+					resource.TestCheckResourceAttr(
+						"sakuracloud_gslb.foobar", "servers.#", "2"),
+					resource.TestCheckResourceAttr(
+						"sakuracloud_gslb.foobar", "servers.0.weight", "3"),
+					resource.TestCheckResourceAttr(
+						"sakuracloud_gslb.foobar", "servers.1.enabled", "false"),
 				),
 			},
 		},
@@ -161,3 +200,37 @@ resource "sakuracloud_gslb" "foobar" {
     description = "GSLB from TerraForm for SAKURA CLOUD"
     tags = ["hoge1", "hoge2"]
 }`
+
+var testAccCheckSakuraCloudGSLBConfig_withServers = `
+resource "sakuracloud_gslb" "foobar" {
+    name = "terraform.io"
+    health_check = {
+        protocol = "ping"
+        delay_loop = 10
+    }
+    weighted = true
+    servers {
+        ipaddress = "8.8.8.8"
+    }
+    servers {
+        ipaddress = "8.8.4.4"
+    }
+}`
+
+var testAccCheckSakuraCloudGSLBConfig_withServersUpdate = `
+resource "sakuracloud_gslb" "foobar" {
+    name = "terraform.io"
+    health_check = {
+        protocol = "ping"
+        delay_loop = 10
+    }
+    weighted = true
+    servers {
+        ipaddress = "8.8.8.8"
+        weight = 3
+    }
+    servers {
+        ipaddress = "8.8.4.4"
+        enabled = false
+    }
+}`
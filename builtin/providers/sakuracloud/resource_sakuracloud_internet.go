@@ -5,6 +5,9 @@ import (
 	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/sacloud/libsacloud/api"
 	"github.com/sacloud/libsacloud/sacloud"
+	"log"
+	"strings"
+	"sync"
 	"time"
 )
 
@@ -123,6 +126,14 @@ func resourceSakuraCloudInternet() *schema.Resource {
 				Type:     schema.TypeString,
 				Computed: true,
 			},
+			"ipv6_prefix_tail": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"ipv6_table_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
 		},
 	}
 }
@@ -152,6 +163,10 @@ func resourceSakuraCloudInternetCreate(d *schema.ResourceData, meta interface{})
 	opts.NetworkMaskLen = d.Get("nw_mask_len").(int)
 	opts.BandWidthMbps = d.Get("band_width").(int)
 
+	if err := validateInternetBandWidth(client, opts.BandWidthMbps); err != nil {
+		return fmt.Errorf("Failed to create SakuraCloud Internet resource: %s", err)
+	}
+
 	internet, err := client.Internet.Create(opts)
 	if err != nil {
 		return fmt.Errorf("Failed to create SakuraCloud Internet resource: %s", err)
@@ -185,7 +200,7 @@ func resourceSakuraCloudInternetRead(d *schema.ResourceData, meta interface{}) e
 		client.Zone = zone.(string)
 	}
 
-	internet, err := client.Internet.Read(toSakuraCloudID(d.Id()))
+	internet, err := readInternetFollowingSwitch(client, d)
 	if err != nil {
 		return fmt.Errorf("Couldn't find SakuraCloud Internet resource: %s", err)
 	}
@@ -201,6 +216,12 @@ func resourceSakuraCloudInternetUpdate(d *schema.ResourceData, meta interface{})
 		client.Zone = zone.(string)
 	}
 
+	if d.HasChange("band_width") {
+		if err := validateInternetBandWidth(client, d.Get("band_width").(int)); err != nil {
+			return fmt.Errorf("Error updating SakuraCloud Internet bandwidth: %s", err)
+		}
+	}
+
 	internet, err := client.Internet.Read(toSakuraCloudID(d.Id()))
 	if err != nil {
 		return fmt.Errorf("Couldn't find SakuraCloud Internet resource: %s", err)
@@ -233,6 +254,15 @@ func resourceSakuraCloudInternetUpdate(d *schema.ResourceData, meta interface{})
 		if err != nil {
 			return fmt.Errorf("Error updating SakuraCloud Internet bandwidth: %s", err)
 		}
+
+		// changing the bandwidth replaces the router with new ID(the switch and the addresses are kept).
+		// Set the new ID at once so that the state follows it even if the rest of the update fails.
+		replacedInternetIDs.add(d.Id(), internet.GetStrID())
+		d.SetId(internet.GetStrID())
+		err = client.Internet.SleepWhileCreating(internet.ID, client.DefaultTimeoutDuration)
+		if err != nil {
+			return fmt.Errorf("Error updating SakuraCloud Internet bandwidth: %s", err)
+		}
 	}
 
 	// handle ipv6 param
@@ -323,7 +353,7 @@ func resourceSakuraCloudInternetDelete(d *schema.ResourceData, meta interface{})
 		if err != nil {
 			return fmt.Errorf("Error booting SakuraCloud Server resource: %s", err)
 		}
-		err = client.Server.SleepUntilUp(id, client.DefaultTimeoutDuration)
+		err = sleepUntilServerUp(client, id)
 		if err != nil {
 			return fmt.Errorf("Error booting SakuraCloud Server resource: %s", err)
 		}
@@ -378,6 +408,8 @@ func setInternetResourceData(d *schema.ResourceData, client *api.Client, data *s
 		d.Set("ipv6_prefix", nil)
 		d.Set("ipv6_prefix_len", nil)
 		d.Set("ipv6_nw_address", nil)
+		d.Set("ipv6_prefix_tail", nil)
+		d.Set("ipv6_table_id", nil)
 	} else {
 		pref := data.Switch.IPv6Nets[0].IPv6Prefix
 		maskLen := data.Switch.IPv6Nets[0].IPv6PrefixLen
@@ -387,9 +419,97 @@ func setInternetResourceData(d *schema.ResourceData, client *api.Client, data *s
 		d.Set("ipv6_prefix", pref)
 		d.Set("ipv6_prefix_len", maskLen)
 		d.Set("ipv6_nw_address", nwAddress)
+		d.Set("ipv6_prefix_tail", data.Switch.IPv6Nets[0].IPv6PrefixTail)
+		if data.Switch.IPv6Nets[0].IPv6Table != nil {
+			d.Set("ipv6_table_id", data.Switch.IPv6Nets[0].IPv6Table.GetStrID())
+		} else {
+			d.Set("ipv6_table_id", nil)
+		}
 	}
 
 	d.Set("zone", client.Zone)
 	d.SetId(data.GetStrID())
 	return nil
 }
+
+// internetIDReplacements records the router IDs replaced by changing the bandwidth in this run of Terraform.
+// Resources referring to the router see the new ID only at apply time,
+// so their diffs use this to treat the old and the new ID as the same router.
+type internetIDReplacements struct {
+	mu  sync.Mutex
+	ids map[string]string
+}
+
+var replacedInternetIDs = &internetIDReplacements{ids: map[string]string{}}
+
+func (r *internetIDReplacements) add(oldID, newID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.ids[oldID] = newID
+}
+
+// isReplaced returns true if the router of oldID has been replaced with newID, directly or through other IDs
+func (r *internetIDReplacements) isReplaced(oldID, newID string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	id := oldID
+	for i := 0; i < len(r.ids); i++ {
+		next, ok := r.ids[id]
+		if !ok {
+			return false
+		}
+		if next == newID {
+			return true
+		}
+		id = next
+	}
+	return false
+}
+
+// suppressReplacedInternetID is a DiffSuppressFunc for internet_id of the resources referring to a router
+func suppressReplacedInternetID(k, old, new string, d *schema.ResourceData) bool {
+	return old != "" && new != "" && replacedInternetIDs.isReplaced(old, new)
+}
+
+// readInternetFollowingSwitch reads the router of the resource.
+// The router is replaced with new ID when its bandwidth is changed,
+// so the router is looked up from the switch if the ID in the state is gone.
+func readInternetFollowingSwitch(client *api.Client, d *schema.ResourceData) (*sacloud.Internet, error) {
+	internet, err := client.Internet.Read(toSakuraCloudID(d.Id()))
+	if err == nil {
+		return internet, nil
+	}
+
+	switchID, ok := d.GetOk("switch_id")
+	if !ok {
+		return nil, err
+	}
+	sw, swErr := client.Switch.Read(toSakuraCloudID(switchID.(string)))
+	if swErr != nil || sw.Internet == nil {
+		return nil, err
+	}
+	log.Printf("[INFO] SakuraCloud Internet(id:%s) is replaced with id:%d", d.Id(), sw.Internet.ID)
+	return client.Internet.Read(sw.Internet.ID)
+}
+
+// validateInternetBandWidth checks bandWidth is one of the available router plans.
+// It needs the API, so it is called at apply time, not from ValidateFunc.
+// The plans don't have mask length, so the bandwidths available for each nw_mask_len aren't checked.
+func validateInternetBandWidth(client *api.Client, bandWidth int) error {
+	res, err := client.Product.Internet.Reset().Find()
+	if err != nil {
+		return fmt.Errorf("Couldn't find SakuraCloud Internet plans: %s", err)
+	}
+
+	available := []string{}
+	for _, plan := range res.InternetPlans {
+		if !plan.IsAvailable() {
+			continue
+		}
+		if plan.BandWidthMbps == bandWidth {
+			return nil
+		}
+		available = append(available, fmt.Sprintf("%d", plan.BandWidthMbps))
+	}
+	return fmt.Errorf("band_width %d is not available, available values are [%s]", bandWidth, strings.Join(available, "/"))
+}
@@ -10,6 +10,28 @@ import (
 	"testing"
 )
 
+func TestSakuraCloudSuppressReplacedInternetID(t *testing.T) {
+	replacedInternetIDs.add("100000000001", "100000000002")
+	replacedInternetIDs.add("100000000002", "100000000003")
+
+	cases := map[string]struct {
+		old, new string
+		suppress bool
+	}{
+		"replaced":       {old: "100000000001", new: "100000000002", suppress: true},
+		"replaced_twice": {old: "100000000001", new: "100000000003", suppress: true},
+		"other_router":   {old: "100000000001", new: "100000000009"},
+		"reverse":        {old: "100000000002", new: "100000000001"},
+		"create":         {old: "", new: "100000000001"},
+	}
+
+	for name, c := range cases {
+		if suppress := suppressReplacedInternetID("internet_id", c.old, c.new, nil); suppress != c.suppress {
+			t.Errorf("%s: expected %t, got %t", name, c.suppress, suppress)
+		}
+	}
+}
+
 func TestAccResourceSakuraCloudInternet(t *testing.T) {
 	var internet sacloud.Internet
 	resource.Test(t, resource.TestCase{
@@ -51,6 +73,10 @@ func TestAccResourceSakuraCloudInternet(t *testing.T) {
 						"sakuracloud_internet.foobar", "enable_ipv6", "true"),
 					resource.TestCheckResourceAttr(
 						"sakuracloud_internet.foobar", "ipv6_prefix_len", "64"),
+					resource.TestCheckResourceAttrSet(
+						"sakuracloud_internet.foobar", "ipv6_prefix_tail"),
+					resource.TestCheckResourceAttrSet(
+						"sakuracloud_internet.foobar", "ipv6_table_id"),
 				),
 			},
 			{
@@ -77,6 +103,43 @@ func TestAccResourceSakuraCloudInternet(t *testing.T) {
 	})
 }
 
+func TestAccResourceSakuraCloudInternet_BandWidthWithSubnet(t *testing.T) {
+	var internet sacloud.Internet
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckSakuraCloudInternetDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccCheckSakuraCloudInternetConfig_withSubnet, 100),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckSakuraCloudInternetExists("sakuracloud_internet.foobar", &internet),
+					resource.TestCheckResourceAttrPair(
+						"sakuracloud_subnet.foobar", "internet_id",
+						"sakuracloud_internet.foobar", "id"),
+				),
+			},
+			{
+				// the router gets new ID, and the subnet follows it without recreation
+				Config: fmt.Sprintf(testAccCheckSakuraCloudInternetConfig_withSubnet, 250),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckSakuraCloudInternetExists("sakuracloud_internet.foobar", &internet),
+					resource.TestCheckResourceAttr(
+						"sakuracloud_internet.foobar", "band_width", "250"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(testAccCheckSakuraCloudInternetConfig_withSubnet, 250),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair(
+						"sakuracloud_subnet.foobar", "internet_id",
+						"sakuracloud_internet.foobar", "id"),
+				),
+			},
+		},
+	})
+}
+
 func testAccCheckSakuraCloudInternetExists(n string, internet *sacloud.Internet) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		rs, ok := s.RootModule().Resources[n]
@@ -185,3 +248,13 @@ resource "sakuracloud_internet" "foobar" {
     enable_ipv6 = true
 }
 `
+
+var testAccCheckSakuraCloudInternetConfig_withSubnet = `
+resource "sakuracloud_internet" "foobar" {
+    name = "myinternet"
+    band_width = %d
+}
+resource "sakuracloud_subnet" "foobar" {
+    internet_id = "${sakuracloud_internet.foobar.id}"
+    next_hop = "${sakuracloud_internet.foobar.min_ipaddress}"
+}`
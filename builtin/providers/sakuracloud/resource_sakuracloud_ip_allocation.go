@@ -0,0 +1,266 @@
+package sakuracloud
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/hashicorp/terraform/helper/hashcode"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/sacloud/libsacloud/api"
+	"github.com/sacloud/libsacloud/sacloud"
+)
+
+func resourceSakuraCloudIPAllocation() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceSakuraCloudIPAllocationCreate,
+		Read:   resourceSakuraCloudIPAllocationRead,
+		Delete: resourceSakuraCloudIPAllocationDelete,
+
+		Schema: map[string]*schema.Schema{
+			"internet_id": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ForceNew:      true,
+				ValidateFunc:  validateSakuracloudIDType,
+				ConflictsWith: []string{"subnet_id"},
+			},
+			"subnet_id": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ForceNew:      true,
+				ValidateFunc:  validateSakuracloudIDType,
+				ConflictsWith: []string{"internet_id"},
+			},
+			"ipaddress": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ForceNew:     true,
+				ValidateFunc: validateIPv4Address,
+			},
+			"switch_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"zone": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ForceNew:     true,
+				Description:  "target SakuraCloud zone",
+				ValidateFunc: validateStringInWord([]string{"is1a", "is1b", "tk1a", "tk1v"}),
+			},
+		},
+	}
+}
+
+func resourceSakuraCloudIPAllocationCreate(d *schema.ResourceData, meta interface{}) error {
+	c := meta.(*api.Client)
+	client := c.Clone()
+	zone, ok := d.GetOk("zone")
+	if ok {
+		client.Zone = zone.(string)
+	}
+
+	switchID, err := readIPAllocationSwitchID(client, d)
+	if err != nil {
+		return err
+	}
+
+	sakuraMutexKV.Lock(switchID)
+	defer sakuraMutexKV.Unlock(switchID)
+
+	sw, ipList, err := readIPAllocationRange(client, d, switchID)
+	if err != nil {
+		return err
+	}
+	if len(ipList) == 0 {
+		return fmt.Errorf("Failed to create SakuraCloud IPAllocation resource: Switch(id:%s) has no assignable address", switchID)
+	}
+
+	used, err := subnetUsedIPAddresses(client, sw.ID)
+	if err != nil {
+		return err
+	}
+	isFree := func(ip string) bool {
+		return !used[ip] && !sw.HasTag(ipAllocationTag(ip))
+	}
+
+	ipaddress := ""
+	if v, ok := d.GetOk("ipaddress"); ok {
+		requested := v.(string)
+		found := false
+		for _, ip := range ipList {
+			if ip == requested {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("Failed to create SakuraCloud IPAllocation resource: %q is out of the range %s - %s",
+				requested, ipList[0], ipList[len(ipList)-1])
+		}
+		if !isFree(requested) {
+			return fmt.Errorf("Failed to create SakuraCloud IPAllocation resource: %q is already in use", requested)
+		}
+		ipaddress = requested
+	} else {
+		for _, ip := range ipList {
+			if isFree(ip) {
+				ipaddress = ip
+				break
+			}
+		}
+		if ipaddress == "" {
+			return fmt.Errorf("Failed to create SakuraCloud IPAllocation resource: no free address in Switch(id:%s)", switchID)
+		}
+	}
+
+	// the reservation is stored as a tag of the switch so that other Terraform runs can see it
+	sw.AppendTag(ipAllocationTag(ipaddress))
+	if _, err := client.Switch.Update(sw.ID, sw); err != nil {
+		return fmt.Errorf("Failed to create SakuraCloud IPAllocation resource: %s", err)
+	}
+
+	d.Set("ipaddress", ipaddress)
+	d.SetId(ipAllocationIDHash(switchID, ipaddress))
+	return resourceSakuraCloudIPAllocationRead(d, meta)
+}
+
+func resourceSakuraCloudIPAllocationRead(d *schema.ResourceData, meta interface{}) error {
+	c := meta.(*api.Client)
+	client := c.Clone()
+	zone, ok := d.GetOk("zone")
+	if ok {
+		client.Zone = zone.(string)
+	}
+
+	switchID, err := readIPAllocationSwitchID(client, d)
+	if err != nil {
+		return err
+	}
+	sw, ipList, err := readIPAllocationRange(client, d, switchID)
+	if err != nil {
+		return err
+	}
+
+	ipaddress := d.Get("ipaddress").(string)
+	found := false
+	for _, ip := range ipList {
+		if ip == ipaddress {
+			found = true
+			break
+		}
+	}
+	if !found || !sw.HasTag(ipAllocationTag(ipaddress)) {
+		// the range was changed(e.g. the subnet was recreated) or the tag was removed, so the reservation is gone
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("switch_id", sw.GetStrID())
+	d.Set("zone", client.Zone)
+	return nil
+}
+
+func resourceSakuraCloudIPAllocationDelete(d *schema.ResourceData, meta interface{}) error {
+	c := meta.(*api.Client)
+	client := c.Clone()
+	zone, ok := d.GetOk("zone")
+	if ok {
+		client.Zone = zone.(string)
+	}
+
+	switchID := d.Get("switch_id").(string)
+
+	sakuraMutexKV.Lock(switchID)
+	defer sakuraMutexKV.Unlock(switchID)
+
+	sw, err := client.Switch.Read(toSakuraCloudID(switchID))
+	if err != nil {
+		return fmt.Errorf("Couldn't find SakuraCloud Switch resource: %s", err)
+	}
+
+	tag := ipAllocationTag(d.Get("ipaddress").(string))
+	if sw.HasTag(tag) {
+		sw.RemoveTag(tag)
+		if _, err := client.Switch.Update(sw.ID, sw); err != nil {
+			return fmt.Errorf("Error deleting SakuraCloud IPAllocation resource: %s", err)
+		}
+	}
+	return nil
+}
+
+// readIPAllocationSwitchID returns the ID of the switch of the router or the subnet
+func readIPAllocationSwitchID(client *api.Client, d *schema.ResourceData) (string, error) {
+	if subnetID, ok := d.GetOk("subnet_id"); ok {
+		subnet, err := client.Subnet.Read(toSakuraCloudID(subnetID.(string)))
+		if err != nil {
+			return "", fmt.Errorf("Couldn't find SakuraCloud Subnet resource: %s", err)
+		}
+		if subnet.Switch == nil {
+			return "", fmt.Errorf("Error reading SakuraCloud Subnet resource: %s", "switch is nil")
+		}
+		return subnet.Switch.GetStrID(), nil
+	}
+
+	internetID, ok := d.GetOk("internet_id")
+	if !ok {
+		return "", fmt.Errorf("Either internet_id or subnet_id is required")
+	}
+
+	// the router gets new ID when its bandwidth is changed but the switch is kept,
+	// so follow the switch once it is known.
+	if switchID := d.Get("switch_id").(string); switchID != "" {
+		return switchID, nil
+	}
+	internet, err := client.Internet.Read(toSakuraCloudID(internetID.(string)))
+	if err != nil {
+		return "", fmt.Errorf("Couldn't find SakuraCloud Internet resource: %s", err)
+	}
+	return internet.Switch.GetStrID(), nil
+}
+
+// readIPAllocationRange returns the switch and the assignable addresses of the router or the subnet
+func readIPAllocationRange(client *api.Client, d *schema.ResourceData, switchID string) (*sacloud.Switch, []string, error) {
+	sw, err := client.Switch.Read(toSakuraCloudID(switchID))
+	if err != nil {
+		return nil, nil, fmt.Errorf("Couldn't find SakuraCloud Switch resource: %s", err)
+	}
+
+	if subnetID, ok := d.GetOk("subnet_id"); ok {
+		subnet, err := client.Subnet.Read(toSakuraCloudID(subnetID.(string)))
+		if err != nil {
+			return nil, nil, fmt.Errorf("Couldn't find SakuraCloud Subnet resource: %s", err)
+		}
+		ipList := []string{}
+		for _, ip := range subnet.IPAddresses {
+			ipList = append(ipList, ip.IPAddress)
+		}
+		return sw, ipList, nil
+	}
+
+	// update internet_id with the current router
+	if sw.Internet != nil {
+		d.Set("internet_id", sw.Internet.GetStrID())
+	}
+	ipList, err := sw.GetIPAddressList()
+	if err != nil {
+		return nil, nil, fmt.Errorf("Error reading Switch resource(IPAddresses): %s", err)
+	}
+	return sw, ipList, nil
+}
+
+// ipAllocationTagPrefix is the prefix of the switch tags which hold the addresses reserved by sakuracloud_ip_allocation.
+// The addresses aren't used by any NIC until the servers are created, so they are stored in the switch.
+const ipAllocationTagPrefix = "ipalloc-"
+
+func ipAllocationTag(ip string) string {
+	return ipAllocationTagPrefix + ip
+}
+
+func ipAllocationIDHash(switchID string, ipaddress string) string {
+	var buf bytes.Buffer
+	buf.WriteString(switchID)
+	buf.WriteString(ipaddress)
+	return fmt.Sprintf("ipalloc-%d", hashcode.String(buf.String()))
+}
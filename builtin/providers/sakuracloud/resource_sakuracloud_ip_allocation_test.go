@@ -0,0 +1,111 @@
+package sakuracloud
+
+import (
+	"errors"
+	"fmt"
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/sacloud/libsacloud/api"
+	"regexp"
+	"testing"
+)
+
+func TestAccResourceSakuraCloudIPAllocation(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckSakuraCloudInternetDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckSakuraCloudIPAllocationConfig_basic,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair(
+						"sakuracloud_ip_allocation.foo", "ipaddress",
+						"sakuracloud_internet.foobar", "min_ipaddress"),
+					resource.TestCheckResourceAttrPair(
+						"sakuracloud_ip_allocation.foo", "switch_id",
+						"sakuracloud_internet.foobar", "switch_id"),
+					testAccCheckSakuraCloudIPAllocationDistinct("sakuracloud_ip_allocation.foo", "sakuracloud_ip_allocation.bar"),
+					testAccCheckSakuraCloudIPAllocationTagged("sakuracloud_ip_allocation.foo"),
+					testAccCheckSakuraCloudIPAllocationTagged("sakuracloud_ip_allocation.bar"),
+				),
+			},
+			{
+				Config:      testAccCheckSakuraCloudIPAllocationConfig_duplicated,
+				ExpectError: regexp.MustCompile("is already in use"),
+			},
+		},
+	})
+}
+
+func testAccCheckSakuraCloudIPAllocationDistinct(names ...string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		allocated := map[string]string{}
+		for _, n := range names {
+			rs, ok := s.RootModule().Resources[n]
+			if !ok {
+				return fmt.Errorf("Not found: %s", n)
+			}
+			ip := rs.Primary.Attributes["ipaddress"]
+			if ip == "" {
+				return errors.New("No IPAllocation ipaddress is set")
+			}
+			if other, ok := allocated[ip]; ok {
+				return fmt.Errorf("%s and %s have the same ipaddress: %s", other, n, ip)
+			}
+			allocated[ip] = n
+		}
+		return nil
+	}
+}
+
+func testAccCheckSakuraCloudIPAllocationTagged(n string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found: %s", n)
+		}
+
+		client := testAccProvider.Meta().(*api.Client)
+		sw, err := client.Switch.Read(toSakuraCloudID(rs.Primary.Attributes["switch_id"]))
+		if err != nil {
+			return err
+		}
+
+		tag := ipAllocationTag(rs.Primary.Attributes["ipaddress"])
+		if !sw.HasTag(tag) {
+			return fmt.Errorf("Switch doesn't have the tag: %s", tag)
+		}
+		return nil
+	}
+}
+
+var testAccCheckSakuraCloudIPAllocationConfig_basic = `
+resource sakuracloud_internet "foobar" {
+    name = "myinternet"
+}
+resource "sakuracloud_ip_allocation" "foo" {
+    internet_id = "${sakuracloud_internet.foobar.id}"
+    ipaddress = "${sakuracloud_internet.foobar.min_ipaddress}"
+}
+resource "sakuracloud_ip_allocation" "bar" {
+    internet_id = "${sakuracloud_internet.foobar.id}"
+    depends_on = ["sakuracloud_ip_allocation.foo"]
+}`
+
+var testAccCheckSakuraCloudIPAllocationConfig_duplicated = `
+resource sakuracloud_internet "foobar" {
+    name = "myinternet"
+}
+resource "sakuracloud_ip_allocation" "foo" {
+    internet_id = "${sakuracloud_internet.foobar.id}"
+    ipaddress = "${sakuracloud_internet.foobar.min_ipaddress}"
+}
+resource "sakuracloud_ip_allocation" "bar" {
+    internet_id = "${sakuracloud_internet.foobar.id}"
+    depends_on = ["sakuracloud_ip_allocation.foo"]
+}
+resource "sakuracloud_ip_allocation" "baz" {
+    internet_id = "${sakuracloud_internet.foobar.id}"
+    ipaddress = "${sakuracloud_internet.foobar.min_ipaddress}"
+}`
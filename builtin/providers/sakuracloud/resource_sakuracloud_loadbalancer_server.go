@@ -8,6 +8,8 @@ import (
 	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/sacloud/libsacloud/api"
 	"github.com/sacloud/libsacloud/sacloud"
+	"log"
+	"strconv"
 	"strings"
 )
 
@@ -15,6 +17,7 @@ func resourceSakuraCloudLoadBalancerServer() *schema.Resource {
 	return &schema.Resource{
 		Create: resourceSakuraCloudLoadBalancerServerCreate,
 		Read:   resourceSakuraCloudLoadBalancerServerRead,
+		Update: resourceSakuraCloudLoadBalancerServerUpdate,
 		Delete: resourceSakuraCloudLoadBalancerServerDelete,
 		Schema: map[string]*schema.Schema{
 			"load_balancer_vip_id": {
@@ -27,32 +30,29 @@ func resourceSakuraCloudLoadBalancerServer() *schema.Resource {
 				ForceNew: true,
 				Required: true,
 			},
-			//"port": {
-			//	Type:         schema.TypeInt,
-			//	Required:     true,
-			//	ForceNew:     true,
-			//	ValidateFunc: validateIntegerInRange(1, 65535),
-			//},
+			"port": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Computed:     true,
+				ForceNew:     true,
+				ValidateFunc: validateIntegerInRange(1, 65535),
+			},
 			"check_protocol": {
 				Type:         schema.TypeString,
 				Required:     true,
-				ForceNew:     true,
 				ValidateFunc: validateStringInWord(sacloud.AllowLoadBalancerHealthCheckProtocol()),
 			},
 			"check_path": {
 				Type:     schema.TypeString,
 				Optional: true,
-				ForceNew: true,
 			},
 			"check_status": {
 				Type:     schema.TypeString,
 				Optional: true,
-				ForceNew: true,
 			},
 			"enabled": {
 				Type:     schema.TypeBool,
 				Optional: true,
-				ForceNew: true,
 				Default:  true,
 			},
 			"zone": {
@@ -80,16 +80,8 @@ func resourceSakuraCloudLoadBalancerServerCreate(d *schema.ResourceData, meta in
 		return fmt.Errorf("Couldn't parse SakuraCloud LoadBalancer VIP ID: %s", err)
 	}
 
-	//validate
-	protocol := d.Get("check_protocol").(string)
-	switch protocol {
-	case "http", "https":
-		if _, ok := d.GetOk("check_path"); !ok {
-			return fmt.Errorf("'check_path' required when protocol is http/https%s", "")
-		}
-		if _, ok := d.GetOk("check_status"); !ok {
-			return fmt.Errorf("'check_status' required when protocol is http/https%s", "")
-		}
+	if err := validateLoadBalancerServerHealthCheck(d); err != nil {
+		return err
 	}
 
 	sakuraMutexKV.Lock(lbID)
@@ -105,8 +97,10 @@ func resourceSakuraCloudLoadBalancerServerCreate(d *schema.ResourceData, meta in
 		return fmt.Errorf("Couldn't find SakuraCloud LoadBalancer VIP resource: %s", vipID)
 	}
 
-	server := expandLoadBalancerServer(d)
-	server.Port = port
+	server := expandLoadBalancerServer(d, port)
+	if s := findLoadBalancerServer(server, vipSetting.Servers); s != nil {
+		return fmt.Errorf("Failed to create SakuraCloud LoadBalancerServer resource:Duplicate LoadBalancer server: %v", server)
+	}
 	vipSetting.AddServer(server)
 
 	loadBalancer, err = client.LoadBalancer.Update(toSakuraCloudID(lbID), loadBalancer)
@@ -146,21 +140,78 @@ func resourceSakuraCloudLoadBalancerServerRead(d *schema.ResourceData, meta inte
 		return fmt.Errorf("Couldn't find SakuraCloud LoadBalancer VIP resource: %s", vipID)
 	}
 
-	server := expandLoadBalancerServer(d)
-	if s := findLoadBalancerServer(server, vipSetting.Servers); s != nil {
-		return fmt.Errorf("Couldn't find SakuraCloud LoadBalancerServer resource: %v", server)
+	server := findLoadBalancerServer(expandLoadBalancerServer(d, port), vipSetting.Servers)
+	if server == nil {
+		log.Printf("[WARN] SakuraCloud LoadBalancerServer resource is not found on VIP(%s): %s", vipID, d.Id())
+		d.SetId("")
+		return nil
 	}
 
 	d.Set("ipaddress", server.IPAddress)
-	d.Set("check_protocol", server.HealthCheck.Protocol)
-	d.Set("check_path", server.HealthCheck.Path)
-	d.Set("check_status", server.HealthCheck.Status)
-	d.Set("enabled", server.Enabled)
+	serverPort, _ := strconv.Atoi(server.Port)
+	d.Set("port", serverPort)
+	if server.HealthCheck != nil {
+		d.Set("check_protocol", server.HealthCheck.Protocol)
+		d.Set("check_path", server.HealthCheck.Path)
+		d.Set("check_status", server.HealthCheck.Status)
+	}
+	d.Set("enabled", strings.ToLower(server.Enabled) == "true")
 	d.Set("zone", client.Zone)
 
 	return nil
 }
 
+func resourceSakuraCloudLoadBalancerServerUpdate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*api.Client)
+	zone, ok := d.GetOk("zone")
+	if ok {
+		client.Zone = zone.(string)
+	}
+
+	vipID := d.Get("load_balancer_vip_id").(string)
+	lbID, vip, port, err := expandVIPID(vipID)
+	if err != nil {
+		return fmt.Errorf("Couldn't parse SakuraCloud LoadBalancer VIP ID: %s", err)
+	}
+
+	if err := validateLoadBalancerServerHealthCheck(d); err != nil {
+		return err
+	}
+
+	sakuraMutexKV.Lock(lbID)
+	defer sakuraMutexKV.Unlock(lbID)
+
+	loadBalancer, err := client.LoadBalancer.Read(toSakuraCloudID(lbID))
+	if err != nil {
+		return fmt.Errorf("Couldn't find SakuraCloud LoadBalancer resource: %s", err)
+	}
+
+	vipSetting := findLoadBalancerVIPMatchByValue(vip, port, loadBalancer.Settings)
+	if vipSetting == nil {
+		return fmt.Errorf("Couldn't find SakuraCloud LoadBalancer VIP resource: %s", vipID)
+	}
+
+	server := expandLoadBalancerServer(d, port)
+	current := findLoadBalancerServer(server, vipSetting.Servers)
+	if current == nil {
+		return fmt.Errorf("Couldn't find SakuraCloud LoadBalancerServer resource: %v", server)
+	}
+	current.Enabled = server.Enabled
+	current.HealthCheck = server.HealthCheck
+
+	loadBalancer, err = client.LoadBalancer.Update(toSakuraCloudID(lbID), loadBalancer)
+	if err != nil {
+		return fmt.Errorf("Failed to update SakuraCloud LoadBalancerServer resource: %s", err)
+	}
+
+	_, err = client.LoadBalancer.Config(toSakuraCloudID(lbID))
+	if err != nil {
+		return fmt.Errorf("Couldn'd apply SakuraCloud LoadBalancer config: %s", err)
+	}
+
+	return resourceSakuraCloudLoadBalancerServerRead(d, meta)
+}
+
 func resourceSakuraCloudLoadBalancerServerDelete(d *schema.ResourceData, meta interface{}) error {
 	client := meta.(*api.Client)
 	zone, ok := d.GetOk("zone")
@@ -186,8 +237,8 @@ func resourceSakuraCloudLoadBalancerServerDelete(d *schema.ResourceData, meta in
 		return fmt.Errorf("Couldn't find SakuraCloud LoadBalancer VIP resource: %s", vipID)
 	}
 
-	server := expandLoadBalancerServer(d)
-	vipSetting.DeleteServer(server.IPAddress, port)
+	server := expandLoadBalancerServer(d, port)
+	vipSetting.DeleteServer(server.IPAddress, server.Port)
 
 	loadBalancer, err = client.LoadBalancer.Update(toSakuraCloudID(lbID), loadBalancer)
 	if err != nil {
@@ -241,10 +292,16 @@ func loadBalancerServerIDHash(vipID string, s *sacloud.LoadBalancerServer) strin
 	return fmt.Sprintf("%d", hashcode.String(buf.String()))
 }
 
-func expandLoadBalancerServer(d *schema.ResourceData) *sacloud.LoadBalancerServer {
+// expandLoadBalancerServer returns server setting from ResourceData.
+// vipPort is used as server port if port is not specified.
+func expandLoadBalancerServer(d *schema.ResourceData, vipPort string) *sacloud.LoadBalancerServer {
 
 	var server = &sacloud.LoadBalancerServer{}
 	server.IPAddress = d.Get("ipaddress").(string)
+	server.Port = vipPort
+	if p, ok := d.GetOk("port"); ok {
+		server.Port = fmt.Sprintf("%d", p.(int))
+	}
 	server.Enabled = "False"
 	if d.Get("enabled").(bool) {
 		server.Enabled = "True"
@@ -262,6 +319,27 @@ func expandLoadBalancerServer(d *schema.ResourceData) *sacloud.LoadBalancerServe
 	return server
 }
 
+func validateLoadBalancerServerHealthCheck(d *schema.ResourceData) error {
+	protocol := d.Get("check_protocol").(string)
+	_, hasPath := d.GetOk("check_path")
+	_, hasStatus := d.GetOk("check_status")
+
+	switch protocol {
+	case "http", "https":
+		if !hasPath {
+			return fmt.Errorf("'check_path' required when protocol is http/https")
+		}
+		if !hasStatus {
+			return fmt.Errorf("'check_status' required when protocol is http/https")
+		}
+	case "tcp", "ping":
+		if hasPath || hasStatus {
+			return fmt.Errorf("'check_path' and 'check_status' can't be specified when protocol is %s", protocol)
+		}
+	}
+	return nil
+}
+
 func expandVIPID(vipID string) (string, string, string, error) {
 	keys := strings.Split(vipID, "-")
 	if len(keys) != 3 {
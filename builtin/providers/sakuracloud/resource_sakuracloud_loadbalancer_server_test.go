@@ -3,12 +3,50 @@ package sakuracloud
 import (
 	"errors"
 	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/hashicorp/terraform/terraform"
 	"github.com/sacloud/libsacloud/api"
 	"github.com/sacloud/libsacloud/sacloud"
 	"testing"
 )
 
+func TestSakuraCloudLoadBalancerServerValidateHealthCheck(t *testing.T) {
+	cases := map[string]struct {
+		raw map[string]interface{}
+		err bool
+	}{
+		"http": {
+			raw: map[string]interface{}{"check_protocol": "http", "check_path": "/", "check_status": "200"},
+		},
+		"http_without_path": {
+			raw: map[string]interface{}{"check_protocol": "http", "check_status": "200"},
+			err: true,
+		},
+		"tcp": {
+			raw: map[string]interface{}{"check_protocol": "tcp"},
+		},
+		"tcp_with_path": {
+			raw: map[string]interface{}{"check_protocol": "tcp", "check_path": "/"},
+			err: true,
+		},
+		"ping_with_status": {
+			raw: map[string]interface{}{"check_protocol": "ping", "check_status": "200"},
+			err: true,
+		},
+	}
+
+	for name, c := range cases {
+		d := schema.TestResourceDataRaw(t, resourceSakuraCloudLoadBalancerServer().Schema, c.raw)
+		err := validateLoadBalancerServerHealthCheck(d)
+		if c.err && err == nil {
+			t.Errorf("%s: expected error, got nil", name)
+		}
+		if !c.err && err != nil {
+			t.Errorf("%s: unexpected error: %s", name, err)
+		}
+	}
+}
+
 func TestAccResourceSakuraCloudLoadBalancerServer(t *testing.T) {
 	var loadBalancer sacloud.LoadBalancer
 	resource.Test(t, resource.TestCase{
@@ -57,6 +95,19 @@ func TestAccResourceSakuraCloudLoadBalancerServer(t *testing.T) {
 						"sakuracloud_load_balancer_vip.vip1", "servers.#", "2"),
 				),
 			},
+			{
+				Config: testAccCheckSakuraCloudLoadBalancerServerConfig_drain,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						"sakuracloud_load_balancer_server.server01", "enabled", "false"),
+					resource.TestCheckResourceAttr(
+						"sakuracloud_load_balancer_server.server01", "port", "80"),
+					resource.TestCheckResourceAttr(
+						"sakuracloud_load_balancer_server.server02", "check_protocol", "tcp"),
+					resource.TestCheckResourceAttr(
+						"sakuracloud_load_balancer_vip.vip1", "servers.#", "2"),
+				),
+			},
 		},
 	})
 }
@@ -131,3 +182,32 @@ resource "sakuracloud_load_balancer_server" "server02"{
     check_protocol = "ping"
 }
 `
+
+var testAccCheckSakuraCloudLoadBalancerServerConfig_drain = `
+resource "sakuracloud_switch" "sw" {
+    name = "sw"
+}
+resource "sakuracloud_load_balancer" "foobar" {
+    switch_id = "${sakuracloud_switch.sw.id}"
+    VRID = 1
+    ipaddress1 = "192.168.11.101"
+    nw_mask_len = 24
+    name = "name"
+}
+resource "sakuracloud_load_balancer_vip" "vip1" {
+    load_balancer_id = "${sakuracloud_load_balancer.foobar.id}"
+    vip = "192.168.11.201"
+    port = 80
+}
+resource "sakuracloud_load_balancer_server" "server01"{
+    load_balancer_vip_id = "${sakuracloud_load_balancer_vip.vip1.id}"
+    ipaddress = "192.168.11.51"
+    check_protocol = "ping"
+    enabled = false
+}
+resource "sakuracloud_load_balancer_server" "server02"{
+    load_balancer_vip_id = "${sakuracloud_load_balancer_vip.vip1.id}"
+    ipaddress = "192.168.11.52"
+    check_protocol = "tcp"
+}
+`
@@ -7,6 +7,8 @@ import (
 	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/sacloud/libsacloud/api"
 	"github.com/sacloud/libsacloud/sacloud"
+	"log"
+	"strconv"
 )
 
 func resourceSakuraCloudLoadBalancerVIP() *schema.Resource {
@@ -112,12 +114,15 @@ func resourceSakuraCloudLoadBalancerVIPRead(d *schema.ResourceData, meta interfa
 	vipSetting := expandLoadBalancerVIP(d)
 	matchedSetting := findLoadBalancerVIPMatch(vipSetting, loadBalancer.Settings)
 	if matchedSetting == nil {
-		return fmt.Errorf("Couldn't find SakuraCloud LoadBalancerVIP resource: %v", vipSetting)
+		log.Printf("[WARN] SakuraCloud LoadBalancerVIP resource is not found: %s", d.Id())
+		d.SetId("")
+		return nil
 	}
 	d.Set("servers", expandLoadBalancerServersFromVIP(loadBalancer.GetStrID(), matchedSetting))
 
-	d.Set("delay_loop", vipSetting.DelayLoop)
-	d.Set("sorry_server", vipSetting.SorryServer)
+	delayLoop, _ := strconv.Atoi(matchedSetting.DelayLoop)
+	d.Set("delay_loop", delayLoop)
+	d.Set("sorry_server", matchedSetting.SorryServer)
 	d.Set("zone", client.Zone)
 
 	return nil
@@ -2,6 +2,9 @@ package sakuracloud
 
 import (
 	"fmt"
+	"log"
+	"strings"
+
 	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/sacloud/libsacloud/api"
 	"github.com/sacloud/libsacloud/sacloud"
@@ -23,8 +26,15 @@ func resourceSakuraCloudNote() *schema.Resource {
 				Required: true,
 			},
 			"content": {
-				Type:     schema.TypeString,
-				Required: true,
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validateNoteContent,
+			},
+			"class": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validateStringInWord(allowNoteClasses),
 			},
 			"description": {
 				Type:     schema.TypeString,
@@ -39,13 +49,26 @@ func resourceSakuraCloudNote() *schema.Resource {
 	}
 }
 
+const (
+	noteClassShell           = "shell"
+	noteClassYAMLCloudConfig = "yaml_cloud_config"
+	cloudConfigHeader        = "#cloud-config"
+)
+
+var allowNoteClasses = []string{noteClassShell, noteClassYAMLCloudConfig}
+
 func resourceSakuraCloudNoteCreate(d *schema.ResourceData, meta interface{}) error {
 	client := meta.(*api.Client)
 
+	if err := validateNoteClassAndContent(d); err != nil {
+		return err
+	}
+
 	opts := client.Note.New()
 
 	opts.Name = d.Get("name").(string)
 	opts.Content = d.Get("content").(string)
+	opts.Class = noteClass(d)
 	if description, ok := d.GetOk("description"); ok {
 		opts.Description = description.(string)
 	}
@@ -77,6 +100,10 @@ func resourceSakuraCloudNoteRead(d *schema.ResourceData, meta interface{}) error
 func resourceSakuraCloudNoteUpdate(d *schema.ResourceData, meta interface{}) error {
 	client := meta.(*api.Client)
 
+	if err := validateNoteClassAndContent(d); err != nil {
+		return err
+	}
+
 	note, err := client.Note.Read(toSakuraCloudID(d.Id()))
 	if err != nil {
 		return fmt.Errorf("Couldn't find SakuraCloud Note resource: %s", err)
@@ -88,6 +115,9 @@ func resourceSakuraCloudNoteUpdate(d *schema.ResourceData, meta interface{}) err
 	if d.HasChange("content") {
 		note.Content = d.Get("content").(string)
 	}
+	if d.HasChange("class") {
+		note.Class = d.Get("class").(string)
+	}
 	if d.HasChange("description") {
 		if description, ok := d.GetOk("description"); ok {
 			note.Description = description.(string)
@@ -129,9 +159,45 @@ func setNoteResourceData(d *schema.ResourceData, _ *api.Client, data *sacloud.No
 
 	d.Set("name", data.Name)
 	d.Set("content", data.Content)
+	if data.Class == "" {
+		d.Set("class", noteClassShell)
+	} else {
+		d.Set("class", data.Class)
+	}
 	d.Set("description", data.Description)
 	d.Set("tags", data.Tags)
 
 	d.SetId(data.GetStrID())
 	return nil
 }
+
+// noteClass returns class of the note.
+// If class is omitted, it is inferred from the header of content.
+func noteClass(d *schema.ResourceData) string {
+	if class, ok := d.GetOk("class"); ok {
+		return class.(string)
+	}
+	if strings.HasPrefix(d.Get("content").(string), cloudConfigHeader) {
+		return noteClassYAMLCloudConfig
+	}
+	return noteClassShell
+}
+
+// validateNoteClassAndContent validates content with class.
+// This can't be done in ValidateFunc because it requires multiple fields.
+func validateNoteClassAndContent(d *schema.ResourceData) error {
+	content := d.Get("content").(string)
+	isCloudConfig := strings.HasPrefix(content, cloudConfigHeader)
+	switch noteClass(d) {
+	case noteClassYAMLCloudConfig:
+		if !isCloudConfig {
+			return fmt.Errorf("content must start with %q when class is %q", cloudConfigHeader, noteClassYAMLCloudConfig)
+		}
+	case noteClassShell:
+		// shell notes with cloud-config content were allowed before class was added, so they aren't rejected
+		if isCloudConfig {
+			log.Printf("[WARN] content of Note(%s) starts with %q, set class to %q to use it as cloud-config", d.Get("name").(string), cloudConfigHeader, noteClassYAMLCloudConfig)
+		}
+	}
+	return nil
+}
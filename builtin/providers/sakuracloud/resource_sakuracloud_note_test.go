@@ -4,12 +4,89 @@ import (
 	"errors"
 	"fmt"
 	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/hashicorp/terraform/terraform"
 	"github.com/sacloud/libsacloud/api"
 	"github.com/sacloud/libsacloud/sacloud"
+	"regexp"
 	"testing"
 )
 
+func TestSakuraCloudNoteValidateContent(t *testing.T) {
+	cases := map[string]struct {
+		content string
+		err     bool
+	}{
+		"shell": {
+			content: "#!/bin/sh\n\techo foo\n",
+		},
+		"mapping": {
+			content: "#cloud-config\nhostname: foo\nruncmd:\n  - echo foo\n",
+		},
+		"block_sequence_at_column_0": {
+			content: "#cloud-config\npackages:\n- vim\n- git\n-\n",
+		},
+		"tab_indent": {
+			content: "#cloud-config\nruncmd:\n\t- echo foo\n",
+			err:     true,
+		},
+		"not_mapping": {
+			content: "#cloud-config\nhostname foo\n",
+			err:     true,
+		},
+	}
+
+	for name, c := range cases {
+		_, errs := validateNoteContent(c.content, "content")
+		if c.err && len(errs) == 0 {
+			t.Errorf("%s: expected error, got nil", name)
+		}
+		if !c.err && len(errs) > 0 {
+			t.Errorf("%s: unexpected error: %s", name, errs)
+		}
+	}
+}
+
+func TestSakuraCloudNoteClass(t *testing.T) {
+	cases := map[string]struct {
+		raw   map[string]interface{}
+		class string
+		err   bool
+	}{
+		"inferred_shell": {
+			raw:   map[string]interface{}{"name": "foo", "content": "#!/bin/sh"},
+			class: noteClassShell,
+		},
+		"inferred_cloud_config": {
+			raw:   map[string]interface{}{"name": "foo", "content": "#cloud-config\nhostname: foo"},
+			class: noteClassYAMLCloudConfig,
+		},
+		"shell_with_cloud_config": {
+			raw:   map[string]interface{}{"name": "foo", "content": "#cloud-config\nhostname: foo", "class": noteClassShell},
+			class: noteClassShell,
+		},
+		"cloud_config_without_header": {
+			raw:   map[string]interface{}{"name": "foo", "content": "#!/bin/sh", "class": noteClassYAMLCloudConfig},
+			class: noteClassYAMLCloudConfig,
+			err:   true,
+		},
+	}
+
+	for name, c := range cases {
+		d := schema.TestResourceDataRaw(t, resourceSakuraCloudNote().Schema, c.raw)
+		if class := noteClass(d); class != c.class {
+			t.Errorf("%s: expected class %q, got %q", name, c.class, class)
+		}
+		err := validateNoteClassAndContent(d)
+		if c.err && err == nil {
+			t.Errorf("%s: expected error, got nil", name)
+		}
+		if !c.err && err != nil {
+			t.Errorf("%s: unexpected error: %s", name, err)
+		}
+	}
+}
+
 func TestAccResourceSakuraCloudNote(t *testing.T) {
 	var note sacloud.Note
 	resource.Test(t, resource.TestCase{
@@ -45,6 +122,35 @@ func TestAccResourceSakuraCloudNote(t *testing.T) {
 	})
 }
 
+func TestAccResourceSakuraCloudNote_CloudConfig(t *testing.T) {
+	var note sacloud.Note
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckSakuraCloudNoteDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckSakuraCloudNoteConfig_cloudConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckSakuraCloudNoteExists("sakuracloud_note.foobar", &note),
+					resource.TestCheckResourceAttr(
+						"sakuracloud_note.foobar", "class", "yaml_cloud_config"),
+					func(s *terraform.State) error {
+						if note.Class != "yaml_cloud_config" {
+							return fmt.Errorf("Bad note class: %s", note.Class)
+						}
+						return nil
+					},
+				),
+			},
+			{
+				Config:      testAccCheckSakuraCloudNoteConfig_invalidCloudConfig,
+				ExpectError: regexp.MustCompile("tabs can't be used"),
+			},
+		},
+	})
+}
+
 func testAccCheckSakuraCloudNoteExists(n string, note *sacloud.Note) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		rs, ok := s.RootModule().Resources[n]
@@ -105,3 +211,22 @@ resource "sakuracloud_note" "foobar" {
     name = "mynote_upd"
     content = "content_upd"
 }`
+
+var testAccCheckSakuraCloudNoteConfig_cloudConfig = `
+resource "sakuracloud_note" "foobar" {
+    name = "mynote"
+    class = "yaml_cloud_config"
+    content = <<EOF
+#cloud-config
+package_upgrade: true
+packages:
+  - nginx
+EOF
+}`
+
+var testAccCheckSakuraCloudNoteConfig_invalidCloudConfig = `
+resource "sakuracloud_note" "foobar" {
+    name = "mynote"
+    class = "yaml_cloud_config"
+    content = "#cloud-config\npackages:\n\t- nginx\n"
+}`
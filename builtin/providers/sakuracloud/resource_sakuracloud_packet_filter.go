@@ -26,47 +26,7 @@ func resourceSakuraCloudPacketFilter() *schema.Resource {
 				Type:     schema.TypeString,
 				Optional: true,
 			},
-			"expressions": {
-				Type:     schema.TypeList,
-				Optional: true,
-				Elem: &schema.Resource{
-					Schema: map[string]*schema.Schema{
-						"protocol": {
-							Type:         schema.TypeString,
-							Required:     true,
-							ForceNew:     true,
-							ValidateFunc: validateStringInWord(sacloud.AllowPacketFilterProtocol()),
-						},
-
-						"source_nw": {
-							Type:     schema.TypeString,
-							Optional: true,
-							Default:  "",
-						},
-
-						"source_port": {
-							Type:     schema.TypeString,
-							Optional: true,
-							Default:  "",
-						},
-						"dest_port": {
-							Type:     schema.TypeString,
-							Optional: true,
-							Default:  "",
-						},
-						"allow": {
-							Type:     schema.TypeBool,
-							Optional: true,
-							Default:  true,
-						},
-						"description": {
-							Type:     schema.TypeString,
-							Optional: true,
-							Default:  "",
-						},
-					},
-				},
-			},
+			"expressions": packetFilterExpressionsSchema(),
 			"zone": {
 				Type:         schema.TypeString,
 				Optional:     true,
@@ -94,37 +54,11 @@ func resourceSakuraCloudPacketFilterCreate(d *schema.ResourceData, meta interfac
 		opts.Description = description.(string)
 	}
 
-	if rawExpressions, ok := d.GetOk("expressions"); ok {
-		expressions := rawExpressions.([]interface{})
-		for _, e := range expressions {
-			exp := e.(map[string]interface{})
-			protocol := exp["protocol"].(string)
-			sourceNW := exp["source_nw"].(string)
-			sourcePort := exp["source_port"].(string)
-			destPort := exp["dest_port"].(string)
-			allow := exp["allow"].(bool)
-			desc := exp["description"].(string)
-
-			var err error
-			switch protocol {
-			case "tcp":
-				err = opts.AddTCPRule(sourceNW, sourcePort, destPort, desc, allow)
-			case "udp":
-				err = opts.AddUDPRule(sourceNW, sourcePort, destPort, desc, allow)
-			case "icmp":
-				err = opts.AddICMPRule(sourceNW, desc, allow)
-			case "fragment":
-				err = opts.AddFragmentRule(sourceNW, desc, allow)
-			case "ip":
-				err = opts.AddIPRule(sourceNW, desc, allow)
-			}
-
-			if err != nil {
-				return err
-			}
-
-		}
+	expressions, err := expandPacketFilterExpressions(d.Get("expressions").([]interface{}))
+	if err != nil {
+		return err
 	}
+	opts.Expression = expressions
 
 	filter, err := client.PacketFilter.Create(opts)
 	if err != nil {
@@ -148,6 +82,8 @@ func resourceSakuraCloudPacketFilterRead(d *schema.ResourceData, meta interface{
 		return fmt.Errorf("Couldn't find SakuraCloud PacketFilter resource: %s", err)
 	}
 
+	// the expressions managed by sakuracloud_packet_filter_rules aren't part of this resource
+	filter.Expression, _ = splitPacketFilterRulesExpressions(filter.Expression)
 	return setPacketFilterResourceData(d, client, filter)
 }
 
@@ -159,6 +95,9 @@ func resourceSakuraCloudPacketFilterUpdate(d *schema.ResourceData, meta interfac
 		client.Zone = zone.(string)
 	}
 
+	sakuraMutexKV.Lock(d.Id())
+	defer sakuraMutexKV.Unlock(d.Id())
+
 	filter, err := client.PacketFilter.Read(toSakuraCloudID(d.Id()))
 	if err != nil {
 		return fmt.Errorf("Couldn't find SakuraCloud PacketFilter resource: %s", err)
@@ -176,39 +115,18 @@ func resourceSakuraCloudPacketFilterUpdate(d *schema.ResourceData, meta interfac
 	}
 
 	if d.HasChange("expressions") {
-		filter.ClearRules()
-		if rawExpressions, ok := d.GetOk("expressions"); ok {
-			expressions := rawExpressions.([]interface{})
-			for _, e := range expressions {
-				exp := e.(map[string]interface{})
-				protocol := exp["protocol"].(string)
-				sourceNW := exp["source_nw"].(string)
-				sourcePort := exp["source_port"].(string)
-				destPort := exp["dest_port"].(string)
-				allow := exp["allow"].(bool)
-				desc := exp["description"].(string)
-
-				var err error
-				switch protocol {
-				case "tcp":
-					err = filter.AddTCPRule(sourceNW, sourcePort, destPort, desc, allow)
-				case "udp":
-					err = filter.AddUDPRule(sourceNW, sourcePort, destPort, desc, allow)
-				case "icmp":
-					err = filter.AddICMPRule(sourceNW, desc, allow)
-				case "fragment":
-					err = filter.AddFragmentRule(sourceNW, desc, allow)
-				case "ip":
-					err = filter.AddIPRule(sourceNW, desc, allow)
-				}
-
-				if err != nil {
-					return err
-				}
-
-			}
+		expressions, err := expandPacketFilterExpressions(d.Get("expressions").([]interface{}))
+		if err != nil {
+			return err
 		}
-
+		// keep the expressions managed by sakuracloud_packet_filter_rules after the own expressions
+		_, groups := splitPacketFilterRulesExpressions(filter.Expression)
+		expressions = append(expressions, groups...)
+		if len(expressions) > packetFilterMaxExpressions {
+			return fmt.Errorf("Error updating SakuraCloud PacketFilter resource: too many expressions(%d), max is %d including sakuracloud_packet_filter_rules",
+				len(expressions), packetFilterMaxExpressions)
+		}
+		filter.Expression = expressions
 	}
 
 	filter, err = client.PacketFilter.Update(filter.ID, filter)
@@ -254,32 +172,125 @@ func setPacketFilterResourceData(d *schema.ResourceData, client *api.Client, dat
 	d.Set("name", data.Name)
 	d.Set("description", data.Description)
 
-	if data.Expression != nil && len(data.Expression) > 0 {
-		expressions := []interface{}{}
-		for _, exp := range data.Expression {
-			expression := map[string]interface{}{}
-			protocol := exp.Protocol
+	d.Set("expressions", flattenPacketFilterExpressions(data.Expression))
+
+	d.Set("zone", client.Zone)
+	d.SetId(data.GetStrID())
+	return nil
+}
+
+// packetFilterMaxExpressions is the maximum number of expressions in a packet filter accepted by the API
+const packetFilterMaxExpressions = 30
+
+func packetFilterExpressionsSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeList,
+		Optional: true,
+		MaxItems: packetFilterMaxExpressions,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"protocol": {
+					Type:         schema.TypeString,
+					Required:     true,
+					ValidateFunc: validateStringInWord(sacloud.AllowPacketFilterProtocol()),
+				},
+
+				"source_nw": {
+					Type:         schema.TypeString,
+					Optional:     true,
+					Default:      "",
+					ValidateFunc: validatePacketFilterSourceNetwork,
+				},
+
+				"source_port": {
+					Type:         schema.TypeString,
+					Optional:     true,
+					Default:      "",
+					ValidateFunc: validatePacketFilterPort,
+				},
+				"dest_port": {
+					Type:         schema.TypeString,
+					Optional:     true,
+					Default:      "",
+					ValidateFunc: validatePacketFilterPort,
+				},
+				"allow": {
+					Type:     schema.TypeBool,
+					Optional: true,
+					Default:  true,
+				},
+				"description": {
+					Type:         schema.TypeString,
+					Optional:     true,
+					Default:      "",
+					ValidateFunc: validatePacketFilterExpressionDescription,
+				},
+			},
+		},
+	}
+}
+
+// expandPacketFilterExpressions builds the expressions in the order of the configuration.
+// Ports are only meaningful for tcp/udp, so ports with icmp/fragment/ip are rejected
+// instead of being dropped silently by the API.
+func expandPacketFilterExpressions(rawExpressions []interface{}) ([]*sacloud.PacketFilterExpression, error) {
+	filter := sacloud.CreateNewPacketFilter()
+	for i, e := range rawExpressions {
+		exp := e.(map[string]interface{})
+		protocol := exp["protocol"].(string)
+		sourceNW := exp["source_nw"].(string)
+		sourcePort := exp["source_port"].(string)
+		destPort := exp["dest_port"].(string)
+		allow := exp["allow"].(bool)
+		desc := exp["description"].(string)
+
+		var err error
+		switch protocol {
+		case "tcp":
+			err = filter.AddTCPRule(sourceNW, sourcePort, destPort, desc, allow)
+		case "udp":
+			err = filter.AddUDPRule(sourceNW, sourcePort, destPort, desc, allow)
+		case "icmp", "fragment", "ip":
+			if sourcePort != "" || destPort != "" {
+				return nil, fmt.Errorf("expressions.%d: source_port and dest_port can't be used with protocol %q, they are only for [tcp/udp]", i, protocol)
+			}
 			switch protocol {
-			case "tcp", "udp":
-				expression["source_nw"] = exp.SourceNetwork
-				expression["source_port"] = exp.SourcePort
-				expression["dest_port"] = exp.DestinationPort
-			case "icmp", "fragment", "ip":
-				expression["source_nw"] = exp.SourceNetwork
+			case "icmp":
+				err = filter.AddICMPRule(sourceNW, desc, allow)
+			case "fragment":
+				err = filter.AddFragmentRule(sourceNW, desc, allow)
+			case "ip":
+				err = filter.AddIPRule(sourceNW, desc, allow)
 			}
+		default:
+			err = fmt.Errorf("expressions.%d: unsupported protocol %q", i, protocol)
+		}
 
-			expression["protocol"] = exp.Protocol
-			expression["allow"] = (exp.Action == "allow")
-			expression["description"] = exp.Description
-
-			expressions = append(expressions, expression)
+		if err != nil {
+			return nil, err
 		}
-		d.Set("expressions", expressions)
-	} else {
-		d.Set("expressions", []interface{}{})
 	}
+	return filter.Expression, nil
+}
 
-	d.Set("zone", client.Zone)
-	d.SetId(data.GetStrID())
-	return nil
+func flattenPacketFilterExpressions(expressions []*sacloud.PacketFilterExpression) []interface{} {
+	results := []interface{}{}
+	for _, exp := range expressions {
+		expression := map[string]interface{}{}
+		switch exp.Protocol {
+		case "tcp", "udp":
+			expression["source_nw"] = exp.SourceNetwork
+			expression["source_port"] = exp.SourcePort
+			expression["dest_port"] = exp.DestinationPort
+		case "icmp", "fragment", "ip":
+			expression["source_nw"] = exp.SourceNetwork
+		}
+
+		expression["protocol"] = exp.Protocol
+		expression["allow"] = (exp.Action == "allow")
+		expression["description"] = exp.Description
+
+		results = append(results, expression)
+	}
+	return results
 }
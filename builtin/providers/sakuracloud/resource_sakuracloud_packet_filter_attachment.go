@@ -0,0 +1,206 @@
+package sakuracloud
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/hashicorp/terraform/helper/hashcode"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/sacloud/libsacloud/api"
+	"github.com/sacloud/libsacloud/sacloud"
+	"log"
+)
+
+func resourceSakuraCloudPacketFilterAttachment() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceSakuraCloudPacketFilterAttachmentCreate,
+		Read:   resourceSakuraCloudPacketFilterAttachmentRead,
+		Update: resourceSakuraCloudPacketFilterAttachmentUpdate,
+		Delete: resourceSakuraCloudPacketFilterAttachmentDelete,
+
+		Schema: map[string]*schema.Schema{
+			"server_id": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validateSakuracloudIDType,
+			},
+			"nic_index": {
+				Type:         schema.TypeInt,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validateIntegerInRange(0, sacloud.ServerMaxInterfaceLen-1),
+			},
+			"packet_filter_id": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validateSakuracloudIDType,
+			},
+			"interface_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"zone": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ForceNew:     true,
+				Description:  "target SakuraCloud zone",
+				ValidateFunc: validateStringInWord([]string{"is1a", "is1b", "tk1a", "tk1v"}),
+			},
+		},
+	}
+}
+
+func resourceSakuraCloudPacketFilterAttachmentCreate(d *schema.ResourceData, meta interface{}) error {
+	c := meta.(*api.Client)
+	client := c.Clone()
+	zone, ok := d.GetOk("zone")
+	if ok {
+		client.Zone = zone.(string)
+	}
+
+	serverID := d.Get("server_id").(string)
+	sakuraMutexKV.Lock(serverID)
+	defer sakuraMutexKV.Unlock(serverID)
+
+	nic, err := readPacketFilterAttachmentInterface(client, serverID, d.Get("nic_index").(int))
+	if err != nil {
+		return err
+	}
+	if nic == nil {
+		return fmt.Errorf("Failed to create SakuraCloud PacketFilterAttachment resource: Server(id:%s) has no NIC at index %d", serverID, d.Get("nic_index").(int))
+	}
+	if nic.PacketFilter != nil {
+		return fmt.Errorf("Failed to create SakuraCloud PacketFilterAttachment resource: NIC(id:%d) already has PacketFilter(id:%s)",
+			nic.ID, nic.PacketFilter.GetStrID())
+	}
+
+	_, err = client.Interface.ConnectToPacketFilter(nic.ID, toSakuraCloudID(d.Get("packet_filter_id").(string)))
+	if err != nil {
+		return fmt.Errorf("Failed to create SakuraCloud PacketFilterAttachment resource: %s", err)
+	}
+
+	d.SetId(packetFilterAttachmentIDHash(serverID, d.Get("nic_index").(int)))
+	return resourceSakuraCloudPacketFilterAttachmentRead(d, meta)
+}
+
+func resourceSakuraCloudPacketFilterAttachmentRead(d *schema.ResourceData, meta interface{}) error {
+	c := meta.(*api.Client)
+	client := c.Clone()
+	zone, ok := d.GetOk("zone")
+	if ok {
+		client.Zone = zone.(string)
+	}
+
+	nic, err := readPacketFilterAttachmentInterface(client, d.Get("server_id").(string), d.Get("nic_index").(int))
+	if err != nil {
+		return err
+	}
+	if nic == nil {
+		log.Printf("[WARN] SakuraCloud PacketFilterAttachment resource is not found, server or NIC was deleted: %s", d.Id())
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("interface_id", nic.GetStrID())
+	if nic.PacketFilter != nil {
+		d.Set("packet_filter_id", nic.PacketFilter.GetStrID())
+	} else {
+		d.Set("packet_filter_id", "")
+	}
+	d.Set("zone", client.Zone)
+
+	return nil
+}
+
+func resourceSakuraCloudPacketFilterAttachmentUpdate(d *schema.ResourceData, meta interface{}) error {
+	c := meta.(*api.Client)
+	client := c.Clone()
+	zone, ok := d.GetOk("zone")
+	if ok {
+		client.Zone = zone.(string)
+	}
+
+	serverID := d.Get("server_id").(string)
+	sakuraMutexKV.Lock(serverID)
+	defer sakuraMutexKV.Unlock(serverID)
+
+	nic, err := readPacketFilterAttachmentInterface(client, serverID, d.Get("nic_index").(int))
+	if err != nil {
+		return err
+	}
+	if nic == nil {
+		return fmt.Errorf("Couldn't find SakuraCloud PacketFilterAttachment resource: Server(id:%s) or its NIC was deleted", serverID)
+	}
+
+	if d.HasChange("packet_filter_id") {
+		if nic.PacketFilter != nil {
+			_, err := client.Interface.DisconnectFromPacketFilter(nic.ID)
+			if err != nil {
+				return fmt.Errorf("Error updating SakuraCloud PacketFilterAttachment resource: %s", err)
+			}
+		}
+		_, err := client.Interface.ConnectToPacketFilter(nic.ID, toSakuraCloudID(d.Get("packet_filter_id").(string)))
+		if err != nil {
+			return fmt.Errorf("Error updating SakuraCloud PacketFilterAttachment resource: %s", err)
+		}
+	}
+
+	return resourceSakuraCloudPacketFilterAttachmentRead(d, meta)
+}
+
+func resourceSakuraCloudPacketFilterAttachmentDelete(d *schema.ResourceData, meta interface{}) error {
+	c := meta.(*api.Client)
+	client := c.Clone()
+	zone, ok := d.GetOk("zone")
+	if ok {
+		client.Zone = zone.(string)
+	}
+
+	serverID := d.Get("server_id").(string)
+	sakuraMutexKV.Lock(serverID)
+	defer sakuraMutexKV.Unlock(serverID)
+
+	nic, err := readPacketFilterAttachmentInterface(client, serverID, d.Get("nic_index").(int))
+	if err != nil {
+		return err
+	}
+	if nic == nil {
+		// the server or NIC was already deleted
+		return nil
+	}
+
+	// only detach the filter this resource attached
+	if nic.PacketFilter != nil && nic.PacketFilter.GetStrID() == d.Get("packet_filter_id").(string) {
+		_, err := client.Interface.DisconnectFromPacketFilter(nic.ID)
+		if err != nil {
+			return fmt.Errorf("Error deleting SakuraCloud PacketFilterAttachment resource: %s", err)
+		}
+	}
+
+	return nil
+}
+
+// readPacketFilterAttachmentInterface returns the NIC of the server, or nil if the server or NIC doesn't exist
+func readPacketFilterAttachmentInterface(client *api.Client, serverID string, index int) (*sacloud.Interface, error) {
+	server, err := client.Server.Read(toSakuraCloudID(serverID))
+	if err != nil {
+		// distinguish deleted server from other errors
+		res, findErr := client.Server.Reset().FilterBy("ID", toSakuraCloudID(serverID)).Find()
+		if findErr == nil && len(res.Servers) == 0 {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("Couldn't find SakuraCloud Server resource: %s", err)
+	}
+	if index >= len(server.Interfaces) {
+		return nil, nil
+	}
+	return &server.Interfaces[index], nil
+}
+
+func packetFilterAttachmentIDHash(serverID string, index int) string {
+	var buf bytes.Buffer
+	buf.WriteString(serverID)
+	buf.WriteString(fmt.Sprintf("%d", index))
+	return fmt.Sprintf("pfattach-%d", hashcode.String(buf.String()))
+}
@@ -0,0 +1,115 @@
+package sakuracloud
+
+import (
+	"fmt"
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/sacloud/libsacloud/api"
+	"testing"
+)
+
+func TestAccResourceSakuraCloudPacketFilterAttachment(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckSakuraCloudPacketFilterAttachmentDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckSakuraCloudPacketFilterAttachmentConfig_basic,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						"sakuracloud_packet_filter_attachment.foobar", "nic_index", "0"),
+					resource.TestCheckResourceAttrPair(
+						"sakuracloud_packet_filter_attachment.foobar", "packet_filter_id",
+						"sakuracloud_packet_filter.foobar", "id"),
+					resource.TestCheckResourceAttrSet(
+						"sakuracloud_packet_filter_attachment.foobar", "interface_id"),
+				),
+			},
+			{
+				Config: testAccCheckSakuraCloudPacketFilterAttachmentConfig_update,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair(
+						"sakuracloud_packet_filter_attachment.foobar", "packet_filter_id",
+						"sakuracloud_packet_filter.foobar2", "id"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckSakuraCloudPacketFilterAttachmentDestroy(s *terraform.State) error {
+	client := testAccProvider.Meta().(*api.Client)
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "sakuracloud_packet_filter_attachment" {
+			continue
+		}
+
+		server, err := client.Server.Read(toSakuraCloudID(rs.Primary.Attributes["server_id"]))
+		if err != nil {
+			continue
+		}
+		if len(server.Interfaces) > 0 && server.Interfaces[0].PacketFilter != nil {
+			return fmt.Errorf("PacketFilter is still attached to Server: %s", server.GetStrID())
+		}
+	}
+
+	return nil
+}
+
+var testAccCheckSakuraCloudPacketFilterAttachmentConfig_basic = `
+resource "sakuracloud_server" "foobar" {
+    name = "myserver_pf_attachment"
+    network_interface = {
+        upstream = "shared"
+    }
+}
+resource "sakuracloud_packet_filter" "foobar" {
+    name = "mypacket_filter"
+    expressions = {
+    	protocol = "tcp"
+    	source_nw = "0.0.0.0"
+    	source_port = "0-65535"
+    	dest_port = "80"
+    	allow = true
+    }
+}
+resource "sakuracloud_packet_filter_attachment" "foobar" {
+    server_id = "${sakuracloud_server.foobar.id}"
+    nic_index = 0
+    packet_filter_id = "${sakuracloud_packet_filter.foobar.id}"
+}`
+
+var testAccCheckSakuraCloudPacketFilterAttachmentConfig_update = `
+resource "sakuracloud_server" "foobar" {
+    name = "myserver_pf_attachment"
+    network_interface = {
+        upstream = "shared"
+    }
+}
+resource "sakuracloud_packet_filter" "foobar" {
+    name = "mypacket_filter"
+    expressions = {
+    	protocol = "tcp"
+    	source_nw = "0.0.0.0"
+    	source_port = "0-65535"
+    	dest_port = "80"
+    	allow = true
+    }
+}
+resource "sakuracloud_packet_filter" "foobar2" {
+    name = "mypacket_filter2"
+    expressions = {
+    	protocol = "tcp"
+    	source_nw = "0.0.0.0"
+    	source_port = "0-65535"
+    	dest_port = "443"
+    	allow = true
+    }
+}
+resource "sakuracloud_packet_filter_attachment" "foobar" {
+    server_id = "${sakuracloud_server.foobar.id}"
+    nic_index = 0
+    packet_filter_id = "${sakuracloud_packet_filter.foobar2.id}"
+}`
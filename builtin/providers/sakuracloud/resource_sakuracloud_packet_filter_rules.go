@@ -0,0 +1,265 @@
+package sakuracloud
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/hashicorp/terraform/helper/hashcode"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/sacloud/libsacloud/api"
+	"github.com/sacloud/libsacloud/sacloud"
+	"regexp"
+	"sort"
+	"strconv"
+)
+
+func resourceSakuraCloudPacketFilterRules() *schema.Resource {
+	expressions := packetFilterExpressionsSchema()
+	expressions.Optional = false
+	expressions.Required = true
+
+	return &schema.Resource{
+		Create: resourceSakuraCloudPacketFilterRulesCreate,
+		Read:   resourceSakuraCloudPacketFilterRulesRead,
+		Update: resourceSakuraCloudPacketFilterRulesUpdate,
+		Delete: resourceSakuraCloudPacketFilterRulesDelete,
+
+		Schema: map[string]*schema.Schema{
+			"packet_filter_id": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validateSakuracloudIDType,
+			},
+			"name": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validatePacketFilterRulesName,
+			},
+			"order": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      0,
+				ValidateFunc: validateIntegerInRange(0, 9999),
+			},
+			"expressions": expressions,
+			"zone": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ForceNew:     true,
+				Description:  "target SakuraCloud zone",
+				ValidateFunc: validateStringInWord([]string{"is1a", "is1b", "tk1a", "tk1v"}),
+			},
+		},
+	}
+}
+
+func resourceSakuraCloudPacketFilterRulesCreate(d *schema.ResourceData, meta interface{}) error {
+	c := meta.(*api.Client)
+	client := c.Clone()
+	zone, ok := d.GetOk("zone")
+	if ok {
+		client.Zone = zone.(string)
+	}
+
+	filterID := d.Get("packet_filter_id").(string)
+	sakuraMutexKV.Lock(filterID)
+	defer sakuraMutexKV.Unlock(filterID)
+
+	filter, err := client.PacketFilter.Read(toSakuraCloudID(filterID))
+	if err != nil {
+		return fmt.Errorf("Couldn't find SakuraCloud PacketFilter resource: %s", err)
+	}
+
+	name := d.Get("name").(string)
+	_, groups := splitPacketFilterRulesExpressions(filter.Expression)
+	for _, exp := range groups {
+		if n, _, _ := parsePacketFilterRulesDescription(exp.Description); n == name {
+			return fmt.Errorf("Failed to create SakuraCloud PacketFilterRules resource: PacketFilter(id:%s) already has rules named %q", filterID, name)
+		}
+	}
+
+	if err := updatePacketFilterRules(client, filter, name, d.Get("order").(int), d.Get("expressions").([]interface{})); err != nil {
+		return fmt.Errorf("Failed to create SakuraCloud PacketFilterRules resource: %s", err)
+	}
+
+	d.SetId(packetFilterRulesIDHash(filterID, name))
+	return resourceSakuraCloudPacketFilterRulesRead(d, meta)
+}
+
+func resourceSakuraCloudPacketFilterRulesRead(d *schema.ResourceData, meta interface{}) error {
+	c := meta.(*api.Client)
+	client := c.Clone()
+	zone, ok := d.GetOk("zone")
+	if ok {
+		client.Zone = zone.(string)
+	}
+
+	filter, err := client.PacketFilter.Read(toSakuraCloudID(d.Get("packet_filter_id").(string)))
+	if err != nil {
+		return fmt.Errorf("Couldn't find SakuraCloud PacketFilter resource: %s", err)
+	}
+
+	name := d.Get("name").(string)
+	order := -1
+	expressions := []*sacloud.PacketFilterExpression{}
+	_, groups := splitPacketFilterRulesExpressions(filter.Expression)
+	for _, exp := range groups {
+		n, o, desc := parsePacketFilterRulesDescription(exp.Description)
+		if n != name {
+			continue
+		}
+		e := *exp
+		e.Description = desc
+		expressions = append(expressions, &e)
+		order = o
+	}
+
+	if len(expressions) == 0 {
+		// the rules were removed from the filter outside of Terraform
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("order", order)
+	d.Set("expressions", flattenPacketFilterExpressions(expressions))
+	d.Set("zone", client.Zone)
+	return nil
+}
+
+func resourceSakuraCloudPacketFilterRulesUpdate(d *schema.ResourceData, meta interface{}) error {
+	c := meta.(*api.Client)
+	client := c.Clone()
+	zone, ok := d.GetOk("zone")
+	if ok {
+		client.Zone = zone.(string)
+	}
+
+	filterID := d.Get("packet_filter_id").(string)
+	sakuraMutexKV.Lock(filterID)
+	defer sakuraMutexKV.Unlock(filterID)
+
+	filter, err := client.PacketFilter.Read(toSakuraCloudID(filterID))
+	if err != nil {
+		return fmt.Errorf("Couldn't find SakuraCloud PacketFilter resource: %s", err)
+	}
+
+	if d.HasChange("order") || d.HasChange("expressions") {
+		err := updatePacketFilterRules(client, filter, d.Get("name").(string), d.Get("order").(int), d.Get("expressions").([]interface{}))
+		if err != nil {
+			return fmt.Errorf("Error updating SakuraCloud PacketFilterRules resource: %s", err)
+		}
+	}
+
+	return resourceSakuraCloudPacketFilterRulesRead(d, meta)
+}
+
+func resourceSakuraCloudPacketFilterRulesDelete(d *schema.ResourceData, meta interface{}) error {
+	c := meta.(*api.Client)
+	client := c.Clone()
+	zone, ok := d.GetOk("zone")
+	if ok {
+		client.Zone = zone.(string)
+	}
+
+	filterID := d.Get("packet_filter_id").(string)
+	sakuraMutexKV.Lock(filterID)
+	defer sakuraMutexKV.Unlock(filterID)
+
+	filter, err := client.PacketFilter.Read(toSakuraCloudID(filterID))
+	if err != nil {
+		return fmt.Errorf("Couldn't find SakuraCloud PacketFilter resource: %s", err)
+	}
+
+	if err := updatePacketFilterRules(client, filter, d.Get("name").(string), 0, nil); err != nil {
+		return fmt.Errorf("Error deleting SakuraCloud PacketFilterRules resource: %s", err)
+	}
+	return nil
+}
+
+// updatePacketFilterRules replaces the rules named name in the filter with rawExpressions.
+// The own expressions of the filter come first, then the rules are placed in ascending order of (order, name).
+// The rules are removed from the filter when rawExpressions is empty.
+func updatePacketFilterRules(client *api.Client, filter *sacloud.PacketFilter, name string, order int, rawExpressions []interface{}) error {
+	rules, err := expandPacketFilterExpressions(rawExpressions)
+	if err != nil {
+		return err
+	}
+	for _, exp := range rules {
+		exp.Description = formatPacketFilterRulesDescription(name, order, exp.Description)
+	}
+
+	own, groups := splitPacketFilterRulesExpressions(filter.Expression)
+	others := []*sacloud.PacketFilterExpression{}
+	for _, exp := range groups {
+		if n, _, _ := parsePacketFilterRulesDescription(exp.Description); n != name {
+			others = append(others, exp)
+		}
+	}
+	groups = append(others, rules...)
+	sort.SliceStable(groups, func(i, j int) bool {
+		ni, oi, _ := parsePacketFilterRulesDescription(groups[i].Description)
+		nj, oj, _ := parsePacketFilterRulesDescription(groups[j].Description)
+		if oi != oj {
+			return oi < oj
+		}
+		return ni < nj
+	})
+
+	expressions := append(own, groups...)
+	if len(expressions) > packetFilterMaxExpressions {
+		return fmt.Errorf("too many expressions in PacketFilter(id:%s): %d, max is %d", filter.GetStrID(), len(expressions), packetFilterMaxExpressions)
+	}
+	filter.Expression = expressions
+
+	if _, err := client.PacketFilter.Update(filter.ID, filter); err != nil {
+		return err
+	}
+	return nil
+}
+
+// packetFilterRulesDescriptionPattern matches the marker put at the head of the description
+// of the expressions managed by sakuracloud_packet_filter_rules, e.g. "[base:10] allow ssh"
+var packetFilterRulesDescriptionPattern = regexp.MustCompile(`^\[([0-9A-Za-z_\-]{1,32}):([0-9]{1,4})\] ?`)
+
+func formatPacketFilterRulesDescription(name string, order int, description string) string {
+	marker := fmt.Sprintf("[%s:%d]", name, order)
+	if description == "" {
+		return marker
+	}
+	return marker + " " + description
+}
+
+// parsePacketFilterRulesDescription returns the name and the order of the rules and the original description.
+// name is empty if the expression isn't managed by sakuracloud_packet_filter_rules.
+func parsePacketFilterRulesDescription(description string) (string, int, string) {
+	m := packetFilterRulesDescriptionPattern.FindStringSubmatch(description)
+	if m == nil {
+		return "", 0, description
+	}
+	order, _ := strconv.Atoi(m[2])
+	return m[1], order, description[len(m[0]):]
+}
+
+// splitPacketFilterRulesExpressions splits expressions into the own expressions of the filter
+// and the expressions managed by sakuracloud_packet_filter_rules
+func splitPacketFilterRulesExpressions(expressions []*sacloud.PacketFilterExpression) ([]*sacloud.PacketFilterExpression, []*sacloud.PacketFilterExpression) {
+	own := []*sacloud.PacketFilterExpression{}
+	groups := []*sacloud.PacketFilterExpression{}
+	for _, exp := range expressions {
+		if name, _, _ := parsePacketFilterRulesDescription(exp.Description); name != "" {
+			groups = append(groups, exp)
+		} else {
+			own = append(own, exp)
+		}
+	}
+	return own, groups
+}
+
+func packetFilterRulesIDHash(filterID string, name string) string {
+	var buf bytes.Buffer
+	buf.WriteString(filterID)
+	buf.WriteString(name)
+	return fmt.Sprintf("pfrules-%d", hashcode.String(buf.String()))
+}
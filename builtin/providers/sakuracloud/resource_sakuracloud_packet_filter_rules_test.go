@@ -0,0 +1,126 @@
+package sakuracloud
+
+import (
+	"errors"
+	"fmt"
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/sacloud/libsacloud/sacloud"
+	"testing"
+)
+
+func TestAccResourceSakuraCloudPacketFilterRules(t *testing.T) {
+	var filter sacloud.PacketFilter
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckSakuraCloudPacketFilterDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckSakuraCloudPacketFilterRulesConfig_basic,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckSakuraCloudPacketFilterExists("sakuracloud_packet_filter.foobar", &filter),
+					resource.TestCheckResourceAttr(
+						"sakuracloud_packet_filter.foobar", "expressions.#", "1"),
+					resource.TestCheckResourceAttr(
+						"sakuracloud_packet_filter_rules.base", "expressions.#", "2"),
+					resource.TestCheckResourceAttr(
+						"sakuracloud_packet_filter_rules.base", "expressions.0.description", "ssh from office"),
+					resource.TestCheckResourceAttr(
+						"sakuracloud_packet_filter_rules.deny", "expressions.#", "1"),
+					testAccCheckSakuraCloudPacketFilterExpressions(&filter, []string{"tcp", "tcp", "icmp", "ip"}),
+				),
+			},
+			{
+				Config: testAccCheckSakuraCloudPacketFilterRulesConfig_update,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckSakuraCloudPacketFilterExists("sakuracloud_packet_filter.foobar", &filter),
+					resource.TestCheckResourceAttr(
+						"sakuracloud_packet_filter_rules.base", "order", "20"),
+					resource.TestCheckResourceAttr(
+						"sakuracloud_packet_filter_rules.base", "expressions.#", "1"),
+					testAccCheckSakuraCloudPacketFilterExpressions(&filter, []string{"tcp", "ip", "tcp"}),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckSakuraCloudPacketFilterExpressions(filter *sacloud.PacketFilter, protocols []string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		if len(filter.Expression) != len(protocols) {
+			return fmt.Errorf("Bad PacketFilter expressions count: expected %d, got %d", len(protocols), len(filter.Expression))
+		}
+		for i, exp := range filter.Expression {
+			if exp.Protocol != protocols[i] {
+				return errors.New("Bad PacketFilter expressions order")
+			}
+		}
+		return nil
+	}
+}
+
+var testAccCheckSakuraCloudPacketFilterRulesConfig_basic = `
+resource "sakuracloud_packet_filter" "foobar" {
+    name = "mypacket_filter"
+    expressions = {
+    	protocol = "tcp"
+    	dest_port = "80"
+    }
+}
+
+resource "sakuracloud_packet_filter_rules" "deny" {
+    packet_filter_id = "${sakuracloud_packet_filter.foobar.id}"
+    name = "deny"
+    order = 100
+    expressions = {
+    	protocol = "ip"
+    	allow = false
+    }
+}
+
+resource "sakuracloud_packet_filter_rules" "base" {
+    packet_filter_id = "${sakuracloud_packet_filter.foobar.id}"
+    name = "base"
+    order = 10
+    expressions = {
+    	protocol = "tcp"
+    	source_nw = "192.0.2.0/24"
+    	dest_port = "22"
+    	description = "ssh from office"
+    }
+    expressions = {
+    	protocol = "icmp"
+    }
+}`
+
+var testAccCheckSakuraCloudPacketFilterRulesConfig_update = `
+resource "sakuracloud_packet_filter" "foobar" {
+    name = "mypacket_filter"
+    expressions = {
+    	protocol = "tcp"
+    	dest_port = "80"
+    }
+}
+
+resource "sakuracloud_packet_filter_rules" "deny" {
+    packet_filter_id = "${sakuracloud_packet_filter.foobar.id}"
+    name = "deny"
+    order = 10
+    expressions = {
+    	protocol = "ip"
+    	allow = false
+    }
+}
+
+resource "sakuracloud_packet_filter_rules" "base" {
+    packet_filter_id = "${sakuracloud_packet_filter.foobar.id}"
+    name = "base"
+    order = 20
+    expressions = {
+    	protocol = "tcp"
+    	source_nw = "192.0.2.0/24"
+    	dest_port = "22"
+    	description = "ssh from office"
+    }
+}`
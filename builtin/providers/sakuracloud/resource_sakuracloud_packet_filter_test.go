@@ -7,9 +7,44 @@ import (
 	"github.com/hashicorp/terraform/terraform"
 	"github.com/sacloud/libsacloud/api"
 	"github.com/sacloud/libsacloud/sacloud"
+	"regexp"
 	"testing"
 )
 
+func TestSakuraCloudPacketFilterValidators(t *testing.T) {
+	sourceNetworks := map[string]bool{
+		"":                        true,
+		"192.0.2.1":               true,
+		"192.0.2.0/24":            true,
+		"192.0.2.0/255.255.255.0": true,
+		"192.0.2.0/255.0.255.0":   false,
+		"192.0.2.0/0.0.0.255":     false,
+		"192.0.2.0/33":            false,
+		"2001:db8::1":             false,
+		"example.com":             false,
+	}
+	for value, valid := range sourceNetworks {
+		_, errs := validatePacketFilterSourceNetwork(value, "source_nw")
+		if valid != (len(errs) == 0) {
+			t.Errorf("source_nw %q: expected valid=%t, got %v", value, valid, errs)
+		}
+	}
+
+	descriptions := map[string]bool{
+		"":                true,
+		"allow ssh":       true,
+		"allow [base:10]": true,
+		"[base:10]":       false,
+		"[base:10] allow": false,
+	}
+	for value, valid := range descriptions {
+		_, errs := validatePacketFilterExpressionDescription(value, "description")
+		if valid != (len(errs) == 0) {
+			t.Errorf("description %q: expected valid=%t, got %v", value, valid, errs)
+		}
+	}
+}
+
 func TestAccResourceSakuraCloudPacketFilter(t *testing.T) {
 	var filter sacloud.PacketFilter
 	resource.Test(t, resource.TestCase{
@@ -60,6 +95,10 @@ func TestAccResourceSakuraCloudPacketFilter(t *testing.T) {
 						"sakuracloud_packet_filter.foobar", "expressions.4.allow", "true"),
 				),
 			},
+			{
+				Config:      testAccCheckSakuraCloudPacketFilterConfig_invalidPort,
+				ExpectError: regexp.MustCompile("source_port and dest_port can't be used with protocol \"icmp\""),
+			},
 		},
 	})
 }
@@ -166,3 +205,15 @@ resource "sakuracloud_packet_filter" "foobar" {
     	allow = true
     }
 }`
+
+var testAccCheckSakuraCloudPacketFilterConfig_invalidPort = `
+resource "sakuracloud_packet_filter" "foobar" {
+    name = "mypacket_filter_upd"
+    description = "PacketFilter from TerraForm for SAKURA CLOUD"
+    expressions = {
+    	protocol = "icmp"
+    	source_nw = "0.0.0.0"
+    	dest_port = "80"
+    	allow = true
+    }
+}`
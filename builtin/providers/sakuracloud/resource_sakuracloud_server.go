@@ -7,6 +7,9 @@ import (
 	"github.com/sacloud/libsacloud/api"
 	"github.com/sacloud/libsacloud/sacloud"
 	"log"
+	"os"
+	"path/filepath"
+	"strings"
 	"time"
 )
 
@@ -26,14 +29,16 @@ func resourceSakuraCloudServer() *schema.Resource {
 				Required: true,
 			},
 			"core": {
-				Type:     schema.TypeInt,
-				Optional: true,
-				Default:  1,
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      1,
+				ValidateFunc: validateIntegerAtLeast(1),
 			},
 			"memory": {
-				Type:     schema.TypeInt,
-				Optional: true,
-				Default:  1,
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      1,
+				ValidateFunc: validateIntegerAtLeast(1),
 			},
 			"disks": {
 				Type:     schema.TypeList,
@@ -45,14 +50,15 @@ func resourceSakuraCloudServer() *schema.Resource {
 			"base_interface": {
 				Type:          schema.TypeString,
 				Optional:      true,
-				ConflictsWith: []string{"nic"},
+				ConflictsWith: []string{"nic", "network_interface"},
 				Deprecated:    "Use field 'nic' instead",
 			},
 			"nic": {
 				Type:          schema.TypeString,
 				Optional:      true,
-				ConflictsWith: []string{"base_interface"},
+				ConflictsWith: []string{"base_interface", "network_interface"},
 				Default:       "shared",
+				Deprecated:    "Use field 'network_interface' instead",
 			},
 			"cdrom_id": {
 				Type:         schema.TypeString,
@@ -62,26 +68,68 @@ func resourceSakuraCloudServer() *schema.Resource {
 			"additional_interfaces": {
 				Type:          schema.TypeList,
 				Optional:      true,
-				MaxItems:      3,
+				MaxItems:      sacloud.ServerMaxInterfaceLen - 1,
 				Elem:          &schema.Schema{Type: schema.TypeString},
-				ConflictsWith: []string{"additional_nics"},
-				Deprecated:    "Use field 'additional_nics' instead",
+				ConflictsWith: []string{"additional_nics", "network_interface"},
+				Deprecated:    "Use field 'network_interface' instead",
 			},
 			"additional_nics": {
 				Type:          schema.TypeList,
 				Optional:      true,
-				MaxItems:      3,
+				MaxItems:      sacloud.ServerMaxInterfaceLen - 1,
 				Elem:          &schema.Schema{Type: schema.TypeString},
-				ConflictsWith: []string{"additional_interfaces"},
+				ConflictsWith: []string{"additional_interfaces", "network_interface"},
+				Deprecated:    "Use field 'network_interface' instead",
 			},
 			"packet_filter_ids": {
-				Type:     schema.TypeList,
-				Optional: true,
-				MaxItems: 4,
-				Elem:     &schema.Schema{Type: schema.TypeString},
+				Type:          schema.TypeList,
+				Optional:      true,
+				MaxItems:      sacloud.ServerMaxInterfaceLen,
+				Elem:          &schema.Schema{Type: schema.TypeString},
+				ConflictsWith: []string{"network_interface"},
+				Deprecated:    "Use field 'network_interface' instead",
 				// ! Current terraform(v0.7) is not support to array validation !
 				// ValidateFunc: validateSakuracloudIDArrayType,
 			},
+			"network_interface": {
+				Type:          schema.TypeList,
+				Optional:      true,
+				MaxItems:      sacloud.ServerMaxInterfaceLen,
+				ConflictsWith: []string{"nic", "base_interface", "additional_nics", "additional_interfaces", "packet_filter_ids"},
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"upstream": {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validateServerNICUpstream,
+						},
+						"user_ip_address": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Computed:     true,
+							ValidateFunc: validateIPv4Address,
+						},
+						"packet_filter_id": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Computed:     true,
+							ValidateFunc: validateSakuracloudIDType,
+						},
+						"mac_address": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"ip_address": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"hostname": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
 			"description": {
 				Type:     schema.TypeString,
 				Optional: true,
@@ -91,6 +139,35 @@ func resourceSakuraCloudServer() *schema.Resource {
 				Optional: true,
 				Elem:     &schema.Schema{Type: schema.TypeString},
 			},
+			"power_state": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validateStringInWord(allowServerPowerStates),
+			},
+			"reboot_triggers": {
+				Type:     schema.TypeMap,
+				Optional: true,
+			},
+			"boot_keystrokes": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"keys": {
+							Type:     schema.TypeList,
+							Required: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+						"wait": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							Default:      1,
+							ValidateFunc: validateIntegerInRange(0, 600),
+						},
+					},
+				},
+			},
 			"zone": {
 				Type:         schema.TypeString,
 				Optional:     true,
@@ -104,6 +181,10 @@ func resourceSakuraCloudServer() *schema.Resource {
 				Computed: true,
 				Elem:     &schema.Schema{Type: schema.TypeString},
 			},
+			"hostname": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
 			"base_nw_ipaddress": {
 				Type:          schema.TypeString,
 				Optional:      true,
@@ -163,6 +244,31 @@ func resourceSakuraCloudServer() *schema.Resource {
 				Computed:      true,
 				ConflictsWith: []string{"base_nw_mask_len"},
 			},
+			"plan_change_history": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"server_id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"core": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"memory": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+					},
+				},
+			},
+			"previous_ids": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
 		},
 	}
 }
@@ -188,6 +294,61 @@ var serverSchemaMigrateDef = []migrateSchemaDef{
 		source:      "base_nw_mask_len",
 		destination: "nw_mask_len",
 	},
+	{
+		source:      "nic",
+		destination: "network_interface",
+		convert:     migrateServerNetworkInterfaces,
+	},
+	{
+		source:      "additional_nics",
+		destination: "network_interface",
+		convert:     migrateServerNetworkInterfaces,
+	},
+	{
+		source:      "packet_filter_ids",
+		destination: "network_interface",
+		convert:     migrateServerNetworkInterfaces,
+	},
+}
+
+// migrateServerNetworkInterfaces converts nic/additional_nics/packet_filter_ids to network_interface
+// if network_interface isn't specified.
+func migrateServerNetworkInterfaces(d *schema.ResourceData) (interface{}, bool) {
+	if _, ok := d.GetOk("network_interface"); ok {
+		return nil, false
+	}
+
+	var upstreams []string
+	if v, ok := d.GetOk("base_interface"); ok {
+		upstreams = append(upstreams, v.(string))
+	} else {
+		upstreams = append(upstreams, d.Get("nic").(string))
+	}
+	additionalNICs := d.Get("additional_nics").([]interface{})
+	if v, ok := d.GetOk("additional_interfaces"); ok {
+		additionalNICs = v.([]interface{})
+	}
+	for _, switchID := range additionalNICs {
+		upstreams = append(upstreams, forceString(switchID))
+	}
+	filterIDs := d.Get("packet_filter_ids").([]interface{})
+
+	var nics []interface{}
+	for i, upstream := range upstreams {
+		if upstream == "" {
+			upstream = serverNICUpstreamDisconnect
+		}
+		filterID := ""
+		if i < len(filterIDs) {
+			filterID = forceString(filterIDs[i])
+		}
+		nics = append(nics, map[string]interface{}{
+			"upstream":         upstream,
+			"user_ip_address":  "",
+			"packet_filter_id": filterID,
+		})
+	}
+	return nics, true
 }
 
 func resourceSakuraCloudServerCreate(d *schema.ResourceData, meta interface{}) error {
@@ -198,37 +359,30 @@ func resourceSakuraCloudServerCreate(d *schema.ResourceData, meta interface{}) e
 		client.Zone = zone.(string)
 	}
 
-	migrateResourceData(d, meta, serverSchemaMigrateDef)
+	rd := migrateResourceData(d, meta, serverSchemaMigrateDef)
+
+	nics, err := expandServerNetworkInterfaces(rd)
+	if err != nil {
+		return err
+	}
 
 	opts := client.Server.New()
 	opts.Name = d.Get("name").(string)
 
-	planID, err := client.Product.Server.GetBySpec(d.Get("core").(int), d.Get("memory").(int))
+	planID, err := getServerPlanBySpec(client, d.Get("core").(int), d.Get("memory").(int))
 	if err != nil {
-		return fmt.Errorf("Invalid server plan.Please change 'core' or 'memory': %s", err)
+		return err
 	}
 	opts.SetServerPlanByID(planID.GetStrID())
 
-	if hasSharedInterface, ok := d.GetOk("nic"); ok {
-		switch forceString(hasSharedInterface) {
-		case "shared":
+	for _, nic := range nics {
+		switch nic.upstream {
+		case serverNICUpstreamShared:
 			opts.AddPublicNWConnectedParam()
-		case "":
+		case serverNICUpstreamDisconnect:
 			opts.AddEmptyConnectedParam()
 		default:
-			opts.AddExistsSwitchConnectedParam(forceString(hasSharedInterface))
-		}
-	} else {
-		opts.AddPublicNWConnectedParam()
-	}
-
-	if interfaces, ok := d.GetOk("additional_nics"); ok {
-		for _, switchID := range interfaces.([]interface{}) {
-			if switchID == nil {
-				opts.AddEmptyConnectedParam()
-			} else {
-				opts.AddExistsSwitchConnectedParam(switchID.(string))
-			}
+			opts.AddExistsSwitchConnectedParam(nic.upstream)
 		}
 	}
 
@@ -274,7 +428,7 @@ func resourceSakuraCloudServerCreate(d *schema.ResourceData, meta interface{}) e
 					if server.Interfaces[0].Switch.Scope == sacloud.ESCopeShared {
 						isNeedEditDisk = true
 					} else {
-						baseIP := forceString(d.Get("ipaddress"))
+						baseIP := serverBaseIPAddress(rd, nics)
 						baseGateway := forceString(d.Get("gateway"))
 						baseMaskLen := forceString(d.Get("nw_mask_len"))
 
@@ -308,20 +462,10 @@ func resourceSakuraCloudServerCreate(d *schema.ResourceData, meta interface{}) e
 		}
 	}
 
-	if rawPacketFilterIDs, ok := d.GetOk("packet_filter_ids"); ok {
-		packetFilterIDs := rawPacketFilterIDs.([]interface{})
-		for i, filterID := range packetFilterIDs {
-			strFilterID := ""
-			if filterID != nil {
-				strFilterID = filterID.(string)
-			}
-			if server.Interfaces != nil && len(server.Interfaces) > i && strFilterID != "" {
-				_, err := client.Interface.ConnectToPacketFilter(server.Interfaces[i].ID, toSakuraCloudID(strFilterID))
-				if err != nil {
-					return fmt.Errorf("Error connecting packet filter: %s", err)
-				}
-			}
-		}
+	// user IP addresses and packet filters
+	err = updateServerNetworkInterfaces(client, server, nics)
+	if err != nil {
+		return err
 	}
 
 	if rawCDROMID, ok := d.GetOk("cdrom_id"); ok {
@@ -334,13 +478,21 @@ func resourceSakuraCloudServerCreate(d *schema.ResourceData, meta interface{}) e
 
 	d.SetId(server.GetStrID())
 
+	if d.Get("power_state").(string) == serverPowerStateDown {
+		return resourceSakuraCloudServerRead(d, meta)
+	}
+
 	//boot
 	_, err = client.Server.Boot(toSakuraCloudID(d.Id()))
 
 	if err != nil {
 		return fmt.Errorf("Failed to boot SakuraCloud Server resource: %s", err)
 	}
-	err = client.Server.SleepUntilUp(toSakuraCloudID(d.Id()), client.DefaultTimeoutDuration)
+	err = sendServerBootKeystrokes(client, toSakuraCloudID(d.Id()), d.Get("boot_keystrokes").([]interface{}))
+	if err != nil {
+		return fmt.Errorf("Failed to boot SakuraCloud Server resource: %s", err)
+	}
+	err = sleepUntilServerUp(client, toSakuraCloudID(d.Id()))
 	if err != nil {
 		return fmt.Errorf("Failed to boot SakuraCloud Server resource: %s", err)
 	}
@@ -377,26 +529,51 @@ func resourceSakuraCloudServerUpdate(r *schema.ResourceData, meta interface{}) e
 
 	shutdownFunc := client.Server.Stop
 
+	// validate the new plan before changing anything
+	var plan *sacloud.ProductServer
+	if d.HasChange("core") || d.HasChange("memory") {
+		p, err := getServerPlanBySpec(client, d.Get("core").(int), d.Get("memory").(int))
+		if err != nil {
+			return err
+		}
+		plan = p
+	}
+
+	nics, err := expandServerNetworkInterfaces(d)
+	if err != nil {
+		return err
+	}
+	isNICChanged := d.HasChange("network_interface")
+
 	server, err := client.Server.Read(toSakuraCloudID(d.Id()))
 	if err != nil {
 		return fmt.Errorf("Couldn't find SakuraCloud Server resource: %s", err)
 	}
+	// reject the changes which can't be applied before the server is stopped
+	if isNICChanged {
+		if err := validateServerNetworkInterfacesUpdate(server, nics); err != nil {
+			return err
+		}
+	}
+
 	isNeedRestart := false
 	isRunning := server.Instance.IsUp()
+	// if power_state is omitted, current power state is kept
+	isDesiredUp := d.Get("power_state").(string) != serverPowerStateDown
+	isBaseIPChanged := isNICChanged && isServerBaseIPAddressChanged(server, nics)
 
-	if d.HasChange("core") || d.HasChange("memory") {
+	if plan != nil {
 		// If planID changed , server ID will change.
-		planID, err := client.Product.Server.GetBySpec(d.Get("core").(int), d.Get("memory").(int))
-		if err != nil {
-			return fmt.Errorf("Invalid server plan.Please change 'core' or 'memory': %s", err)
-		}
-		server.SetServerPlanByID(planID.GetStrID())
+		server.SetServerPlanByID(plan.GetStrID())
 
 		isNeedRestart = true
 	}
 
-	if d.HasChange("disks") || d.HasChange("nic") || d.HasChange("additional_nics") ||
-		d.HasChange("ipaddress") || d.HasChange("gateway") || d.HasChange("nw_mask_len") {
+	if d.HasChange("disks") || d.HasChange("ipaddress") || d.HasChange("gateway") || d.HasChange("nw_mask_len") || isBaseIPChanged {
+		isNeedRestart = true
+	}
+	// switching NICs requires the server to be stopped, but packet filters and user IP addresses don't
+	if isNICChanged && isServerNICConnectionChanged(server, nics) {
 		isNeedRestart = true
 	}
 
@@ -438,85 +615,10 @@ func resourceSakuraCloudServerUpdate(r *schema.ResourceData, meta interface{}) e
 	}
 
 	// NIC
-	if d.HasChange("nic") || d.HasChange("additional_nics") {
-
-		var conf []interface{}
-		if c, ok := d.GetOk("additional_nics"); ok {
-			conf = c.([]interface{})
-		}
-
-		newNICCount := len(conf)
-		for i, nic := range server.Interfaces {
-			if i == 0 {
-				// only when nic has change
-				if d.HasChange("nic") &&
-					server.Interfaces[0].Switch != nil {
-					_, err := client.Interface.DisconnectFromSwitch(server.Interfaces[0].ID)
-					if err != nil {
-						return fmt.Errorf("Error disconnecting NIC from SakuraCloud Switch resource: %s", err)
-					}
-				}
-				continue
-			}
-
-			// disconnect exists NIC
-			if nic.Switch != nil {
-				_, err := client.Interface.DisconnectFromSwitch(nic.ID)
-				if err != nil {
-					return fmt.Errorf("Error disconnecting NIC from SakuraCloud Switch resource: %s", err)
-				}
-			}
-
-			//delete NIC
-			if i > newNICCount {
-				_, err := client.Interface.Delete(nic.ID)
-				if err != nil {
-					return fmt.Errorf("Error deleting SakuraCloud NIC resource: %s", err)
-				}
-			}
-		}
-		// only when nic has change
-		if d.HasChange("nic") {
-			sharedNICCon := d.Get("nic").(string)
-			if sharedNICCon == "shared" {
-				_, err := client.Interface.ConnectToSharedSegment(server.Interfaces[0].ID)
-				if err != nil {
-					return fmt.Errorf("Error connecting NIC to the shared segment: %s", err)
-				}
-			} else if sharedNICCon != "" {
-				_, err := client.Interface.ConnectToSwitch(server.Interfaces[0].ID, toSakuraCloudID(sharedNICCon))
-				if err != nil {
-					return fmt.Errorf("Error connecting NIC to SakuraCloud Switch resource: %s", err)
-				}
-			}
-		}
-
-		for i, s := range conf {
-			switchID := ""
-			if s != nil {
-				switchID = s.(string)
-			}
-			if len(server.Interfaces) <= i+1 {
-				//create NIC
-				nic := client.Interface.New()
-				nic.SetServerID(server.ID)
-				if switchID != "" {
-					nic.SetSwitchID(toSakuraCloudID(switchID))
-				}
-				_, err := client.Interface.Create(nic)
-				if err != nil {
-					return fmt.Errorf("Error creating NIC to SakuraCloud Server resource: %s", err)
-				}
-
-			} else {
-
-				if switchID != "" {
-					_, err := client.Interface.ConnectToSwitch(server.Interfaces[i+1].ID, toSakuraCloudID(switchID))
-					if err != nil {
-						return fmt.Errorf("Error connecting NIC to SakuraCloud Switch resource: %s", err)
-					}
-				}
-			}
+	if isNICChanged {
+		err := updateServerNetworkInterfaces(client, server, nics)
+		if err != nil {
+			return err
 		}
 	}
 
@@ -526,14 +628,14 @@ func resourceSakuraCloudServerUpdate(r *schema.ResourceData, meta interface{}) e
 		return fmt.Errorf("Couldn't find SakuraCloud Server resource: %s", err)
 	}
 
-	if d.HasChange("ipaddress") || d.HasChange("gateway") || d.HasChange("nw_mask_len") {
+	if d.HasChange("ipaddress") || d.HasChange("gateway") || d.HasChange("nw_mask_len") || isBaseIPChanged {
 		if len(updatedServer.Disks) > 0 && len(updatedServer.Interfaces) > 0 && updatedServer.Interfaces[0].Switch != nil {
 			isNeedEditDisk := false
 			diskEditConfig := client.Disk.NewCondig()
 			if updatedServer.Interfaces[0].Switch.Scope == sacloud.ESCopeShared {
 				isNeedEditDisk = true
 			} else {
-				baseIP := forceString(d.Get("ipaddress"))
+				baseIP := serverBaseIPAddress(d, nics)
 				baseGateway := forceString(d.Get("gateway"))
 				baseMaskLen := forceString(d.Get("nw_mask_len"))
 
@@ -556,7 +658,7 @@ func resourceSakuraCloudServerUpdate(r *schema.ResourceData, meta interface{}) e
 						return fmt.Errorf("Error editting SakuraCloud DiskConfig: %s", err)
 					}
 				} else {
-					log.Printf("[WARN] Disk[%s] does not support modify disk", diskID)
+					log.Printf("[WARN] Disk[%d] does not support modify disk", diskID)
 				}
 
 			}
@@ -564,12 +666,25 @@ func resourceSakuraCloudServerUpdate(r *schema.ResourceData, meta interface{}) e
 	}
 
 	// change Plan
-	if d.HasChange("core") || d.HasChange("memory") {
+	if plan != nil {
+		oldCore, _ := d.GetChange("core")
+		oldMemory, _ := d.GetChange("memory")
+		previousID := d.Id()
+
 		server, err := client.Server.ChangePlan(toSakuraCloudID(d.Id()), server.ServerPlan.GetStrID())
 		if err != nil {
 			return fmt.Errorf("Error changing SakuraCloud ServerPlan : %s", err)
 		}
 		d.SetId(server.GetStrID())
+
+		// record IDs before changing plan, they can't be read from API
+		history := append(d.Get("plan_change_history").([]interface{}), map[string]interface{}{
+			"server_id": previousID,
+			"core":      oldCore.(int),
+			"memory":    oldMemory.(int),
+		})
+		d.Set("plan_change_history", history)
+		d.Set("previous_ids", append(d.Get("previous_ids").([]interface{}), previousID))
 	}
 
 	if d.HasChange("name") {
@@ -596,60 +711,6 @@ func resourceSakuraCloudServerUpdate(r *schema.ResourceData, meta interface{}) e
 	}
 	d.SetId(server.GetStrID())
 
-	if d.HasChange("packet_filter_ids") {
-		if rawPacketFilterIDs, ok := d.GetOk("packet_filter_ids"); ok {
-			packetFilterIDs := rawPacketFilterIDs.([]interface{})
-			for i, filterID := range packetFilterIDs {
-				strFilterID := ""
-				if filterID != nil {
-					strFilterID = filterID.(string)
-				}
-				if server.Interfaces != nil && len(server.Interfaces) > i {
-					if server.Interfaces[i].PacketFilter != nil {
-						_, err := client.Interface.DisconnectFromPacketFilter(server.Interfaces[i].ID)
-						if err != nil {
-							return fmt.Errorf("Error disconnecting packet filter: %s", err)
-						}
-					}
-
-					if strFilterID != "" {
-						_, err := client.Interface.ConnectToPacketFilter(server.Interfaces[i].ID, toSakuraCloudID(filterID.(string)))
-						if err != nil {
-							return fmt.Errorf("Error connecting packet filter: %s", err)
-						}
-					}
-				}
-			}
-
-			if len(server.Interfaces) > len(packetFilterIDs) {
-				i := len(packetFilterIDs)
-				for i < len(server.Interfaces) {
-					if server.Interfaces[i].PacketFilter != nil {
-						_, err := client.Interface.DisconnectFromPacketFilter(server.Interfaces[i].ID)
-						if err != nil {
-							return fmt.Errorf("Error disconnecting packet filter: %s", err)
-						}
-					}
-
-					i++
-				}
-
-			}
-
-		} else {
-			if server.Interfaces != nil {
-				for _, i := range server.Interfaces {
-					if i.PacketFilter != nil {
-						_, err := client.Interface.DisconnectFromPacketFilter(i.ID)
-						if err != nil {
-							return fmt.Errorf("Error disconnecting packet filter: %s", err)
-						}
-					}
-				}
-			}
-		}
-	}
-
 	if d.HasChange("cdrom_id") {
 
 		if server.Instance.CDROM != nil {
@@ -668,17 +729,39 @@ func resourceSakuraCloudServerUpdate(r *schema.ResourceData, meta interface{}) e
 		}
 	}
 
-	if isNeedRestart && isRunning {
+	switch {
+	case !isDesiredUp && isRunning && !isNeedRestart:
+		if err := shutdownServer(client, toSakuraCloudID(d.Id())); err != nil {
+			return err
+		}
+	case isDesiredUp && (!isRunning || isNeedRestart):
 		_, err := client.Server.Boot(toSakuraCloudID(d.Id()))
 		if err != nil {
 			return fmt.Errorf("Error booting SakuraCloud Server resource: %s", err)
 		}
 
-		err = client.Server.SleepUntilUp(toSakuraCloudID(d.Id()), client.DefaultTimeoutDuration)
+		// boot_keystrokes are sent only when the server is powered on from the stopped state
+		if d.HasChange("power_state") {
+			err = sendServerBootKeystrokes(client, toSakuraCloudID(d.Id()), d.Get("boot_keystrokes").([]interface{}))
+			if err != nil {
+				return fmt.Errorf("Error booting SakuraCloud Server resource: %s", err)
+			}
+		}
+
+		err = sleepUntilServerUp(client, toSakuraCloudID(d.Id()))
 		if err != nil {
 			return fmt.Errorf("Error booting SakuraCloud Server resource: %s", err)
 		}
+	case isDesiredUp && d.HasChange("reboot_triggers"):
+		_, err := client.Server.RebootForce(toSakuraCloudID(d.Id()))
+		if err != nil {
+			return fmt.Errorf("Error rebooting SakuraCloud Server resource: %s", err)
+		}
 
+		err = sleepUntilServerUp(client, toSakuraCloudID(d.Id()))
+		if err != nil {
+			return fmt.Errorf("Error rebooting SakuraCloud Server resource: %s", err)
+		}
 	}
 
 	return resourceSakuraCloudServerRead(d.RawResourceData(), meta)
@@ -734,37 +817,50 @@ func setServerResourceData(d *schema.ResourceData, client *api.Client, data *sac
 	}
 
 	hasSharedInterface := len(data.Interfaces) > 0 && data.Interfaces[0].Switch != nil
-	if hasSharedInterface {
-		if data.Interfaces[0].Switch.Scope == sacloud.ESCopeShared {
-			if _, ok := d.GetOk("base_interface"); ok {
-				d.Set("base_interface", "shared")
+
+	// nic/additional_nics/packet_filter_ids are kept as configured while network_interface is used
+	_, isNetworkInterface := d.GetOk("network_interface")
+	if isNetworkInterface {
+		d.Set("network_interface", flattenServerNetworkInterfaces(data.Interfaces))
+	} else {
+		if hasSharedInterface {
+			if data.Interfaces[0].Switch.Scope == sacloud.ESCopeShared {
+				if _, ok := d.GetOk("base_interface"); ok {
+					d.Set("base_interface", "shared")
+				}
+				d.Set("nic", "shared")
+			} else {
+				d.Set("nic", data.Interfaces[0].Switch.GetStrID())
+				if _, ok := d.GetOk("base_interface"); ok {
+					d.Set("base_interface", data.Interfaces[0].Switch.GetStrID())
+				}
 			}
-			d.Set("nic", "shared")
 		} else {
-			d.Set("nic", data.Interfaces[0].Switch.GetStrID())
+			d.Set("nic", "")
 			if _, ok := d.GetOk("base_interface"); ok {
-				d.Set("base_interface", data.Interfaces[0].Switch.GetStrID())
+				d.Set("base_interface", "")
 			}
 		}
-	} else {
-		d.Set("nic", "")
-		if _, ok := d.GetOk("base_interface"); ok {
-			d.Set("base_interface", "")
+
+		d.Set("additional_nics", flattenInterfaces(data.Interfaces))
+		if _, ok := d.GetOk("additional_interfaces"); ok {
+			d.Set("additional_interfaces", flattenInterfaces(data.Interfaces))
 		}
-	}
 
-	d.Set("additional_nics", flattenInterfaces(data.Interfaces))
-	if _, ok := d.GetOk("additional_interfaces"); ok {
-		d.Set("additional_interfaces", flattenInterfaces(data.Interfaces))
+		d.Set("packet_filter_ids", flattenPacketFilters(data.Interfaces))
 	}
 
 	d.Set("description", data.Description)
 	d.Set("tags", data.Tags)
-
-	d.Set("packet_filter_ids", flattenPacketFilters(data.Interfaces))
+	if data.Instance.IsUp() {
+		d.Set("power_state", serverPowerStateUp)
+	} else if data.Instance.IsDown() {
+		d.Set("power_state", serverPowerStateDown)
+	}
 
 	//readonly values
 	d.Set("macaddresses", flattenMacAddresses(data.Interfaces))
+	d.Set("hostname", data.HostName)
 
 	d.Set("ipaddress", "")
 	d.Set("base_nw_ipaddress", "")
@@ -808,3 +904,284 @@ func setServerResourceData(d *schema.ResourceData, client *api.Client, data *sac
 	d.SetId(data.GetStrID())
 	return nil
 }
+
+// sendServerBootKeystrokes sends each key sequence of boot_keystrokes to the server console
+// after waiting for its delay.
+func sendServerBootKeystrokes(client *api.Client, serverID int64, keystrokes []interface{}) error {
+	for _, raw := range keystrokes {
+		v := raw.(map[string]interface{})
+		time.Sleep(time.Duration(v["wait"].(int)) * time.Second)
+
+		req := client.Server.NewKeyboardRequest()
+		for _, key := range v["keys"].([]interface{}) {
+			req.Keys = append(req.Keys, key.(string))
+		}
+		if _, err := client.Server.SendKey(serverID, req); err != nil {
+			return fmt.Errorf("Failed to send keystrokes %v: %s", req.Keys, err)
+		}
+	}
+	return nil
+}
+
+// sleepUntilServerUp waits until the server is up.
+// On failure a console snapshot is saved to the temp directory and its path is added to the error.
+func sleepUntilServerUp(client *api.Client, serverID int64) error {
+	err := client.Server.SleepUntilUp(serverID, client.DefaultTimeoutDuration)
+	if err == nil {
+		return nil
+	}
+
+	path := filepath.Join(os.TempDir(), fmt.Sprintf("sakuracloud_server_%d_%d.png", serverID, time.Now().Unix()))
+	if serr := saveServerVNCSnapshot(client, serverID, path); serr != nil {
+		return err
+	}
+	return fmt.Errorf("%s (console snapshot: %s)", err, path)
+}
+
+const (
+	serverPowerStateUp   = "up"
+	serverPowerStateDown = "down"
+)
+
+var allowServerPowerStates = []string{serverPowerStateUp, serverPowerStateDown}
+
+// shutdownServer stops the server gracefully and waits until it is down.
+func shutdownServer(client *api.Client, id int64) error {
+	_, err := client.Server.Shutdown(id)
+	if err != nil {
+		return fmt.Errorf("Error stopping SakuraCloud Server resource: %s", err)
+	}
+
+	err = client.Server.SleepUntilDown(id, client.DefaultTimeoutDuration)
+	if err != nil {
+		return fmt.Errorf("Error stopping SakuraCloud Server resource: %s", err)
+	}
+	return nil
+}
+
+const (
+	serverNICUpstreamShared     = "shared"
+	serverNICUpstreamDisconnect = "disconnect"
+)
+
+type serverNetworkInterface struct {
+	upstream       string
+	userIPAddress  string
+	packetFilterID string
+	// legacy fields(nic/additional_nics) can't specify user IP address of each NIC
+	keepUserIPAddress bool
+	// packet_filter_id of network_interface is omitted, so packet filter attached by other resources is kept
+	keepPacketFilter bool
+}
+
+// expandServerNetworkInterfaces returns desired NICs from network_interface.
+// nic/additional_nics/packet_filter_ids are migrated to network_interface by serverSchemaMigrateDef.
+func expandServerNetworkInterfaces(d resourceData) ([]*serverNetworkInterface, error) {
+	var nics []*serverNetworkInterface
+
+	_, isNetworkInterface := d.RawResourceData().GetOk("network_interface")
+	isLegacy := !isNetworkInterface
+	for _, raw := range d.Get("network_interface").([]interface{}) {
+		v := raw.(map[string]interface{})
+		nic := &serverNetworkInterface{
+			upstream:          v["upstream"].(string),
+			userIPAddress:     v["user_ip_address"].(string),
+			packetFilterID:    v["packet_filter_id"].(string),
+			keepUserIPAddress: isLegacy,
+		}
+		nic.keepPacketFilter = !isLegacy && nic.packetFilterID == ""
+		nics = append(nics, nic)
+	}
+
+	if len(nics) > sacloud.ServerMaxInterfaceLen {
+		return nil, fmt.Errorf("Server can have up to %d NICs", sacloud.ServerMaxInterfaceLen)
+	}
+	for i, nic := range nics {
+		if i > 0 && nic.upstream == serverNICUpstreamShared {
+			return nil, fmt.Errorf("network_interface.%d: only the first NIC can be connected to the shared segment", i)
+		}
+		if nic.userIPAddress != "" && !nic.isConnectedToSwitch() {
+			return nil, fmt.Errorf("network_interface.%d: user_ip_address can be specified only for the NIC connected to a switch", i)
+		}
+	}
+	return nics, nil
+}
+
+func (nic *serverNetworkInterface) isConnectedToSwitch() bool {
+	return nic.upstream != serverNICUpstreamShared && nic.upstream != serverNICUpstreamDisconnect
+}
+
+func flattenServerNetworkInterfaces(interfaces []sacloud.Interface) []interface{} {
+	ret := []interface{}{}
+	for _, i := range interfaces {
+		nic := map[string]interface{}{
+			"upstream":         serverNICUpstream(&i),
+			"user_ip_address":  i.UserIPAddress,
+			"packet_filter_id": "",
+			"mac_address":      strings.ToLower(i.MACAddress),
+			"ip_address":       i.UserIPAddress,
+			"hostname":         i.HostName,
+		}
+		if i.Switch != nil && i.Switch.Scope == sacloud.ESCopeShared {
+			nic["ip_address"] = i.IPAddress
+		}
+		if i.PacketFilter != nil {
+			nic["packet_filter_id"] = i.PacketFilter.GetStrID()
+		}
+		ret = append(ret, nic)
+	}
+	return ret
+}
+
+func serverNICUpstream(i *sacloud.Interface) string {
+	switch {
+	case i.Switch == nil:
+		return serverNICUpstreamDisconnect
+	case i.Switch.Scope == sacloud.ESCopeShared:
+		return serverNICUpstreamShared
+	default:
+		return i.Switch.GetStrID()
+	}
+}
+
+// isServerNICConnectionChanged returns true if NICs will be added, removed or switched
+func isServerNICConnectionChanged(server *sacloud.Server, nics []*serverNetworkInterface) bool {
+	if len(server.Interfaces) != len(nics) {
+		return true
+	}
+	for i, nic := range nics {
+		if serverNICUpstream(&server.Interfaces[i]) != nic.upstream {
+			return true
+		}
+	}
+	return false
+}
+
+func isServerBaseIPAddressChanged(server *sacloud.Server, nics []*serverNetworkInterface) bool {
+	if len(nics) == 0 || nics[0].keepUserIPAddress || nics[0].userIPAddress == "" {
+		return false
+	}
+	return len(server.Interfaces) == 0 || server.Interfaces[0].UserIPAddress != nics[0].userIPAddress
+}
+
+// serverBaseIPAddress returns IP address of the first NIC used by disk edit
+func serverBaseIPAddress(d resourceData, nics []*serverNetworkInterface) string {
+	if ip := forceString(d.Get("ipaddress")); ip != "" {
+		return ip
+	}
+	if len(nics) > 0 {
+		return nics[0].userIPAddress
+	}
+	return ""
+}
+
+// validateServerNetworkInterfacesUpdate checks desired NICs can be applied to the current NICs of the server
+func validateServerNetworkInterfacesUpdate(server *sacloud.Server, nics []*serverNetworkInterface) error {
+	for i, nic := range nics {
+		if i >= len(server.Interfaces) || nic.keepUserIPAddress {
+			continue
+		}
+		if nic.userIPAddress == "" && server.Interfaces[i].UserIPAddress != "" {
+			return fmt.Errorf("network_interface.%d: user_ip_address can't be cleared by Terraform, clear it on the control panel or recreate the server", i)
+		}
+	}
+	return nil
+}
+
+// updateServerNetworkInterfaces connects, creates and deletes NICs of the server to match desired NICs
+func updateServerNetworkInterfaces(client *api.Client, server *sacloud.Server, nics []*serverNetworkInterface) error {
+	for i, nic := range nics {
+		var iface *sacloud.Interface
+		if i < len(server.Interfaces) {
+			iface = &server.Interfaces[i]
+			if serverNICUpstream(iface) != nic.upstream {
+				if iface.Switch != nil {
+					_, err := client.Interface.DisconnectFromSwitch(iface.ID)
+					if err != nil {
+						return fmt.Errorf("Error disconnecting NIC from SakuraCloud Switch resource: %s", err)
+					}
+				}
+				switch nic.upstream {
+				case serverNICUpstreamShared:
+					_, err := client.Interface.ConnectToSharedSegment(iface.ID)
+					if err != nil {
+						return fmt.Errorf("Error connecting NIC to the shared segment: %s", err)
+					}
+				case serverNICUpstreamDisconnect:
+				default:
+					_, err := client.Interface.ConnectToSwitch(iface.ID, toSakuraCloudID(nic.upstream))
+					if err != nil {
+						return fmt.Errorf("Error connecting NIC to SakuraCloud Switch resource: %s", err)
+					}
+				}
+			}
+		} else {
+			//create NIC
+			newNIC := client.Interface.New()
+			newNIC.SetServerID(server.ID)
+			if nic.isConnectedToSwitch() {
+				newNIC.SetSwitchID(toSakuraCloudID(nic.upstream))
+			}
+			created, err := client.Interface.Create(newNIC)
+			if err != nil {
+				return fmt.Errorf("Error creating NIC to SakuraCloud Server resource: %s", err)
+			}
+			iface = created
+		}
+
+		if !nic.keepUserIPAddress && nic.userIPAddress != "" && iface.UserIPAddress != nic.userIPAddress {
+			_, err := client.Interface.Update(iface.ID, &sacloud.Interface{UserIPAddress: nic.userIPAddress})
+			if err != nil {
+				return fmt.Errorf("Error updating user IP address of SakuraCloud NIC resource: %s", err)
+			}
+		}
+
+		currentFilterID := ""
+		if iface.PacketFilter != nil {
+			currentFilterID = iface.PacketFilter.GetStrID()
+		}
+		if !nic.keepPacketFilter && currentFilterID != nic.packetFilterID {
+			if currentFilterID != "" {
+				_, err := client.Interface.DisconnectFromPacketFilter(iface.ID)
+				if err != nil {
+					return fmt.Errorf("Error disconnecting packet filter: %s", err)
+				}
+			}
+			if nic.packetFilterID != "" {
+				_, err := client.Interface.ConnectToPacketFilter(iface.ID, toSakuraCloudID(nic.packetFilterID))
+				if err != nil {
+					return fmt.Errorf("Error connecting packet filter: %s", err)
+				}
+			}
+		}
+	}
+
+	//delete NIC
+	for i := len(nics); i < len(server.Interfaces); i++ {
+		iface := server.Interfaces[i]
+		if iface.Switch != nil {
+			_, err := client.Interface.DisconnectFromSwitch(iface.ID)
+			if err != nil {
+				return fmt.Errorf("Error disconnecting NIC from SakuraCloud Switch resource: %s", err)
+			}
+		}
+		_, err := client.Interface.Delete(iface.ID)
+		if err != nil {
+			return fmt.Errorf("Error deleting SakuraCloud NIC resource: %s", err)
+		}
+	}
+	return nil
+}
+
+// getServerPlanBySpec returns available server plan in the zone.
+// It needs the API, so it is called at the beginning of apply, not from ValidateFunc.
+func getServerPlanBySpec(client *api.Client, core int, memory int) (*sacloud.ProductServer, error) {
+	plan, err := client.Product.Server.GetBySpec(core, memory)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid server plan.Please change 'core' or 'memory': %s", err)
+	}
+	if !plan.IsAvailable() {
+		return nil, fmt.Errorf("Server plan(core:%d, memory:%d) is not available in zone %q.Please change 'core' or 'memory'", core, memory, client.Zone)
+	}
+	return plan, nil
+}
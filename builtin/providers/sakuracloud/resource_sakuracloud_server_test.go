@@ -4,13 +4,128 @@ import (
 	"errors"
 	"fmt"
 	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/hashicorp/terraform/terraform"
 	"github.com/sacloud/libsacloud/api"
 	"github.com/sacloud/libsacloud/sacloud"
+	"reflect"
 	"regexp"
 	"testing"
 )
 
+func TestSakuraCloudServerValidateNetworkInterfacesUpdate(t *testing.T) {
+	server := &sacloud.Server{
+		Interfaces: []sacloud.Interface{
+			{},
+			{UserIPAddress: "192.168.0.11"},
+		},
+	}
+
+	cases := map[string]struct {
+		nics []*serverNetworkInterface
+		err  bool
+	}{
+		"keep": {
+			nics: []*serverNetworkInterface{
+				{upstream: "shared"},
+				{upstream: "123456789012", userIPAddress: "192.168.0.11"},
+			},
+		},
+		"change": {
+			nics: []*serverNetworkInterface{
+				{upstream: "shared"},
+				{upstream: "123456789012", userIPAddress: "192.168.0.12"},
+			},
+		},
+		"legacy": {
+			nics: []*serverNetworkInterface{
+				{upstream: "shared", keepUserIPAddress: true},
+				{upstream: "123456789012", keepUserIPAddress: true},
+			},
+		},
+		"new_nic": {
+			nics: []*serverNetworkInterface{
+				{upstream: "shared"},
+				{upstream: "123456789012", userIPAddress: "192.168.0.11"},
+				{upstream: "123456789012"},
+			},
+		},
+		"clear": {
+			nics: []*serverNetworkInterface{
+				{upstream: "shared"},
+				{upstream: "123456789012"},
+			},
+			err: true,
+		},
+	}
+
+	for name, c := range cases {
+		err := validateServerNetworkInterfacesUpdate(server, c.nics)
+		if c.err && err == nil {
+			t.Errorf("%s: expected error, got nil", name)
+		}
+		if !c.err && err != nil {
+			t.Errorf("%s: unexpected error: %s", name, err)
+		}
+	}
+}
+
+func TestSakuraCloudServerExpandNetworkInterfaces(t *testing.T) {
+	cases := map[string]struct {
+		raw      map[string]interface{}
+		expected []*serverNetworkInterface
+	}{
+		"legacy": {
+			raw: map[string]interface{}{
+				"name":              "foobar",
+				"additional_nics":   []interface{}{"111111111111", ""},
+				"packet_filter_ids": []interface{}{"", "222222222222"},
+			},
+			expected: []*serverNetworkInterface{
+				{upstream: "shared", keepUserIPAddress: true},
+				{upstream: "111111111111", packetFilterID: "222222222222", keepUserIPAddress: true},
+				{upstream: "disconnect", keepUserIPAddress: true},
+			},
+		},
+		"legacy_base_interface": {
+			raw: map[string]interface{}{
+				"name":                  "foobar",
+				"base_interface":        "111111111111",
+				"additional_interfaces": []interface{}{""},
+			},
+			expected: []*serverNetworkInterface{
+				{upstream: "111111111111", keepUserIPAddress: true},
+				{upstream: "disconnect", keepUserIPAddress: true},
+			},
+		},
+		"network_interface": {
+			raw: map[string]interface{}{
+				"name": "foobar",
+				"network_interface": []interface{}{
+					map[string]interface{}{"upstream": "shared", "packet_filter_id": "222222222222"},
+					map[string]interface{}{"upstream": "111111111111", "user_ip_address": "192.168.0.11"},
+				},
+			},
+			expected: []*serverNetworkInterface{
+				{upstream: "shared", packetFilterID: "222222222222"},
+				{upstream: "111111111111", userIPAddress: "192.168.0.11", keepPacketFilter: true},
+			},
+		},
+	}
+
+	for name, c := range cases {
+		d := schema.TestResourceDataRaw(t, resourceSakuraCloudServer().Schema, c.raw)
+		nics, err := expandServerNetworkInterfaces(migrateResourceData(d, nil, serverSchemaMigrateDef))
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", name, err)
+			continue
+		}
+		if !reflect.DeepEqual(nics, c.expected) {
+			t.Errorf("%s: expected %#v, got %#v", name, c.expected, nics)
+		}
+	}
+}
+
 func TestAccResourceSakuraCloudServer(t *testing.T) {
 	var server sacloud.Server
 	resource.Test(t, resource.TestCase{
@@ -49,6 +164,14 @@ func TestAccResourceSakuraCloudServer(t *testing.T) {
 						"sakuracloud_server.foobar", "core", "2"),
 					resource.TestCheckResourceAttr(
 						"sakuracloud_server.foobar", "memory", "2"),
+					resource.TestCheckResourceAttr(
+						"sakuracloud_server.foobar", "plan_change_history.#", "1"),
+					resource.TestCheckResourceAttr(
+						"sakuracloud_server.foobar", "plan_change_history.0.core", "1"),
+					resource.TestCheckResourceAttr(
+						"sakuracloud_server.foobar", "plan_change_history.0.memory", "1"),
+					resource.TestCheckResourceAttr(
+						"sakuracloud_server.foobar", "previous_ids.#", "1"),
 					resource.TestCheckResourceAttr(
 						"sakuracloud_server.foobar", "disks.#", "1"),
 					resource.TestCheckResourceAttr(
@@ -189,6 +312,74 @@ func TestAccSakuraCloudServer_With_BlankDisk(t *testing.T) {
 	})
 }
 
+func TestAccSakuraCloudServer_PowerState(t *testing.T) {
+	var server sacloud.Server
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckSakuraCloudServerDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckSakuraCloudServerConfig_power_state_down,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckSakuraCloudServerExists("sakuracloud_server.foobar", &server),
+					resource.TestCheckResourceAttr(
+						"sakuracloud_server.foobar", "power_state", "down"),
+				),
+			},
+			{
+				Config: testAccCheckSakuraCloudServerConfig_power_state_omitted,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckSakuraCloudServerExists("sakuracloud_server.foobar", &server),
+					resource.TestCheckResourceAttr(
+						"sakuracloud_server.foobar", "power_state", "down"),
+				),
+			},
+			{
+				Config: testAccCheckSakuraCloudServerConfig_power_state_up,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckSakuraCloudServerExists("sakuracloud_server.foobar", &server),
+					resource.TestCheckResourceAttr(
+						"sakuracloud_server.foobar", "power_state", "up"),
+					resource.TestCheckResourceAttr(
+						"sakuracloud_server.foobar", "reboot_triggers.config", "v1"),
+				),
+			},
+			{
+				Config: testAccCheckSakuraCloudServerConfig_power_state_reboot,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckSakuraCloudServerExists("sakuracloud_server.foobar", &server),
+					resource.TestCheckResourceAttr(
+						"sakuracloud_server.foobar", "power_state", "up"),
+					resource.TestCheckResourceAttr(
+						"sakuracloud_server.foobar", "reboot_triggers.config", "v2"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccSakuraCloudServer_BootKeystrokes(t *testing.T) {
+	var server sacloud.Server
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckSakuraCloudServerDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckSakuraCloudServerConfig_boot_keystrokes,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckSakuraCloudServerExists("sakuracloud_server.foobar", &server),
+					resource.TestCheckResourceAttr(
+						"sakuracloud_server.foobar", "power_state", "up"),
+					resource.TestCheckResourceAttr(
+						"sakuracloud_server.foobar", "boot_keystrokes.#", "2"),
+				),
+			},
+		},
+	})
+}
+
 func TestAccSakuraCloudServer_EditConnect_With_Same_Switch(t *testing.T) {
 	var server sacloud.Server
 	resource.Test(t, resource.TestCase{
@@ -242,6 +433,60 @@ func TestAccSakuraCloudServer_EditConnect_With_Same_Switch(t *testing.T) {
 	})
 }
 
+func TestAccSakuraCloudServer_NetworkInterface(t *testing.T) {
+	var server sacloud.Server
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckSakuraCloudServerDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckSakuraCloudServerConfig_network_interface,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckSakuraCloudServerExists("sakuracloud_server.foobar", &server),
+					testAccCheckSakuraCloudServerAttributes(&server),
+					resource.TestCheckResourceAttr(
+						"sakuracloud_server.foobar", "network_interface.#", "2"),
+					resource.TestCheckResourceAttr(
+						"sakuracloud_server.foobar", "network_interface.0.upstream", "shared"),
+					resource.TestMatchResourceAttr("sakuracloud_server.foobar",
+						"network_interface.0.ip_address",
+						regexp.MustCompile(".+")), // should be not empty
+					resource.TestCheckResourceAttrPair(
+						"sakuracloud_server.foobar", "network_interface.1.upstream",
+						"sakuracloud_switch.foobar", "id"),
+					resource.TestCheckResourceAttr(
+						"sakuracloud_server.foobar", "network_interface.1.user_ip_address", "192.168.0.11"),
+					resource.TestCheckResourceAttrPair(
+						"sakuracloud_server.foobar", "network_interface.1.packet_filter_id",
+						"sakuracloud_packet_filter.foobar", "id"),
+					resource.TestCheckResourceAttr(
+						"sakuracloud_server.foobar", "macaddresses.#", "2"),
+				),
+			},
+			{
+				Config: testAccCheckSakuraCloudServerConfig_network_interface_upd,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckSakuraCloudServerExists("sakuracloud_server.foobar", &server),
+					testAccCheckSakuraCloudServerAttributes(&server),
+					resource.TestCheckResourceAttr(
+						"sakuracloud_server.foobar", "network_interface.#", "5"),
+					// omitted packet_filter_id keeps the attached packet filter
+					resource.TestCheckResourceAttrPair(
+						"sakuracloud_server.foobar", "network_interface.1.packet_filter_id",
+						"sakuracloud_packet_filter.foobar", "id"),
+					resource.TestCheckResourceAttr(
+						"sakuracloud_server.foobar", "network_interface.2.upstream", "disconnect"),
+					resource.TestCheckResourceAttr(
+						"sakuracloud_server.foobar", "network_interface.4.user_ip_address", "192.168.0.14"),
+					resource.TestCheckResourceAttr(
+						"sakuracloud_server.foobar", "macaddresses.#", "5"),
+				),
+			},
+		},
+	})
+}
+
 func testAccCheckSakuraCloudServerExists(n string, server *sacloud.Server) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		rs, ok := s.RootModule().Resources[n]
@@ -532,6 +777,64 @@ resource "sakuracloud_disk" "foobar" {
 }
 `
 
+const testAccCheckSakuraCloudServerConfig_power_state_down = `
+resource "sakuracloud_server" "foobar" {
+    name = "myserver_power_state"
+    nic = "shared"
+    power_state = "down"
+}
+`
+
+const testAccCheckSakuraCloudServerConfig_power_state_omitted = `
+resource "sakuracloud_server" "foobar" {
+    name = "myserver_power_state"
+    nic = "shared"
+}
+`
+
+const testAccCheckSakuraCloudServerConfig_power_state_up = `
+resource "sakuracloud_server" "foobar" {
+    name = "myserver_power_state"
+    nic = "shared"
+    power_state = "up"
+    reboot_triggers = {
+        config = "v1"
+    }
+}
+`
+
+const testAccCheckSakuraCloudServerConfig_power_state_reboot = `
+resource "sakuracloud_server" "foobar" {
+    name = "myserver_power_state"
+    nic = "shared"
+    power_state = "up"
+    reboot_triggers = {
+        config = "v2"
+    }
+}
+`
+
+const testAccCheckSakuraCloudServerConfig_boot_keystrokes = `
+data "sakuracloud_cdrom" "ubuntu" {
+    filter = {
+	name = "Name"
+	values = ["Ubuntu Server 16"]
+    }
+}
+resource "sakuracloud_server" "foobar" {
+    name = "myserver_boot_keystrokes"
+    nic = "shared"
+    cdrom_id = "${data.sakuracloud_cdrom.ubuntu.id}"
+    boot_keystrokes = {
+        keys = ["enter"]
+        wait = 10
+    }
+    boot_keystrokes = {
+        keys = ["esc"]
+    }
+}
+`
+
 const testAccCheckSakuraCloudServerConfig_connect_same_sw_before = `
 resource "sakuracloud_switch" "foobar" {
     name = "foobar"
@@ -553,3 +856,67 @@ resource "sakuracloud_server" "foobar" {
     additional_nics = [""]
 }
 `
+
+const testAccCheckSakuraCloudServerConfig_network_interface = `
+resource "sakuracloud_switch" "foobar" {
+    name = "foobar"
+}
+resource "sakuracloud_packet_filter" "foobar" {
+    name = "foobar"
+    expressions = {
+    	protocol = "tcp"
+    	source_nw = "0.0.0.0"
+    	source_port = "0-65535"
+    	dest_port = "80"
+    	allow = true
+    }
+}
+resource "sakuracloud_server" "foobar" {
+    name = "foobar"
+    network_interface {
+        upstream = "shared"
+    }
+    network_interface {
+        upstream = "${sakuracloud_switch.foobar.id}"
+        user_ip_address = "192.168.0.11"
+        packet_filter_id = "${sakuracloud_packet_filter.foobar.id}"
+    }
+}
+`
+
+const testAccCheckSakuraCloudServerConfig_network_interface_upd = `
+resource "sakuracloud_switch" "foobar" {
+    name = "foobar"
+}
+resource "sakuracloud_packet_filter" "foobar" {
+    name = "foobar"
+    expressions = {
+    	protocol = "tcp"
+    	source_nw = "0.0.0.0"
+    	source_port = "0-65535"
+    	dest_port = "80"
+    	allow = true
+    }
+}
+resource "sakuracloud_server" "foobar" {
+    name = "foobar"
+    network_interface {
+        upstream = "shared"
+    }
+    network_interface {
+        upstream = "${sakuracloud_switch.foobar.id}"
+        user_ip_address = "192.168.0.11"
+    }
+    network_interface {
+        upstream = "disconnect"
+    }
+    network_interface {
+        upstream = "${sakuracloud_switch.foobar.id}"
+        user_ip_address = "192.168.0.13"
+    }
+    network_interface {
+        upstream = "${sakuracloud_switch.foobar.id}"
+        user_ip_address = "192.168.0.14"
+    }
+}
+`
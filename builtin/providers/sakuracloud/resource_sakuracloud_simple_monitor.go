@@ -4,11 +4,11 @@ import (
 	"fmt"
 
 	"errors"
-	"github.com/hashicorp/terraform/helper/hashcode"
 	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/sacloud/libsacloud/api"
 	"github.com/sacloud/libsacloud/sacloud"
 	"strconv"
+	"strings"
 )
 
 func resourceSakuraCloudSimpleMonitor() *schema.Resource {
@@ -20,6 +20,8 @@ func resourceSakuraCloudSimpleMonitor() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
+		SchemaVersion: 1,
+		MigrateState:  migrateStateFunc("sakuracloud_simple_monitor", simpleMonitorStateMigrations...),
 
 		Schema: map[string]*schema.Schema{
 			"target": {
@@ -28,8 +30,9 @@ func resourceSakuraCloudSimpleMonitor() *schema.Resource {
 				ForceNew: true,
 			},
 			"health_check": {
-				Type:     schema.TypeSet,
+				Type:     schema.TypeList,
 				Required: true,
+				MaxItems: 1,
 
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
@@ -64,10 +67,15 @@ func resourceSakuraCloudSimpleMonitor() *schema.Resource {
 							Type:     schema.TypeString,
 							Optional: true,
 						},
-						"excepcted_data": {
+						"expected_data": {
 							Type:     schema.TypeString,
 							Optional: true,
 						},
+						"excepcted_data": {
+							Type:       schema.TypeString,
+							Optional:   true,
+							Deprecated: "Use field 'expected_data' instead",
+						},
 						"community": {
 							Type:     schema.TypeString,
 							Optional: true,
@@ -125,10 +133,13 @@ func resourceSakuraCloudSimpleMonitor() *schema.Resource {
 func resourceSakuraCloudSimpleMonitorCreate(d *schema.ResourceData, meta interface{}) error {
 	client := meta.(*api.Client)
 
+	if err := validateSimpleMonitorConfig(d); err != nil {
+		return err
+	}
+
 	opts := client.SimpleMonitor.New(d.Get("target").(string))
 
-	healthCheckConf := d.Get("health_check").(*schema.Set)
-	for _, c := range healthCheckConf.List() {
+	for _, c := range d.Get("health_check").([]interface{}) {
 		conf := c.(map[string]interface{})
 		protocol := conf["protocol"].(string)
 		port := ""
@@ -159,12 +170,12 @@ func resourceSakuraCloudSimpleMonitorCreate(d *schema.ResourceData, meta interfa
 
 		case "dns":
 			opts.SetHealthCheckDNS(forceString(conf["qname"]),
-				forceString(conf["expected_data"]))
+				simpleMonitorExpectedData(conf))
 		case "snmp":
 			opts.SetHealthCheckSNMP(forceString(conf["community"]),
 				forceString(conf["snmp_version"]),
 				forceString(conf["oid"]),
-				forceString(conf["expected_data"]))
+				simpleMonitorExpectedData(conf))
 		case "tcp":
 			opts.SetHealthCheckTCP(port)
 		case "ssh":
@@ -239,14 +250,17 @@ func resourceSakuraCloudSimpleMonitorRead(d *schema.ResourceData, meta interface
 func resourceSakuraCloudSimpleMonitorUpdate(d *schema.ResourceData, meta interface{}) error {
 	client := meta.(*api.Client)
 
+	if err := validateSimpleMonitorConfig(d); err != nil {
+		return err
+	}
+
 	simpleMonitor, err := client.SimpleMonitor.Read(toSakuraCloudID(d.Id()))
 	if err != nil {
 		return fmt.Errorf("Couldn't find SakuraCloud SimpleMonitor resource: %s", err)
 	}
 
 	if d.HasChange("health_check") {
-		healthCheckConf := d.Get("health_check").(*schema.Set)
-		for _, c := range healthCheckConf.List() {
+		for _, c := range d.Get("health_check").([]interface{}) {
 			conf := c.(map[string]interface{})
 			protocol := conf["protocol"].(string)
 			port := ""
@@ -277,12 +291,12 @@ func resourceSakuraCloudSimpleMonitorUpdate(d *schema.ResourceData, meta interfa
 
 			case "dns":
 				simpleMonitor.SetHealthCheckDNS(forceString(conf["qname"]),
-					forceString(conf["expected_data"]))
+					simpleMonitorExpectedData(conf))
 			case "snmp":
 				simpleMonitor.SetHealthCheckSNMP(forceString(conf["community"]),
 					forceString(conf["snmp_version"]),
 					forceString(conf["oid"]),
-					forceString(conf["expected_data"]))
+					simpleMonitorExpectedData(conf))
 			case "tcp":
 				simpleMonitor.SetHealthCheckTCP(port)
 			case "ssh":
@@ -367,39 +381,76 @@ func resourceSakuraCloudSimpleMonitorDelete(d *schema.ResourceData, meta interfa
 	return nil
 }
 
-func healthCheckSimpleMonitorHash(v interface{}) int {
-	target := v.(map[string]interface{})
-
-	protocol := target["protocol"].(string)
-	path := ""
-	status := ""
-	port := ""
-	qname := ""
-	ed := ""
-	community := ""
-	snmpVersion := ""
-	oid := ""
-
-	switch protocol {
-	case "http", "https":
-		path = target["path"].(string)
-		status = target["status"].(string)
-	case "tcp", "ssh", "smtp", "pop3":
-		port = target["port"].(string)
-	case "dns":
-		qname = target["qname"].(string)
-		ed = target["expected_data"].(string)
-	case "snmp":
-		community = target["community"].(string)
-		snmpVersion = target["snmp_version"].(string)
-		oid = target["oid"].(string)
-		ed = target["expected_data"].(string)
+var simpleMonitorStateMigrations = []stateMigrateFunc{
+	// v0 => v1: health_check is changed from set to list.
+	// excepcted_data(typo) is kept as is because configurations of v0 use it,
+	// it is moved to expected_data by Read when the configuration is changed to expected_data.
+	setToListStateAttribute("health_check"),
+}
+
+// simpleMonitorHealthCheckFields are the protocol specific fields of health_check and the protocols which use them
+var simpleMonitorHealthCheckFields = []struct {
+	field     string
+	protocols []string
+}{
+	{field: "host_header", protocols: []string{"http", "https"}},
+	{field: "path", protocols: []string{"http", "https"}},
+	{field: "status", protocols: []string{"http", "https"}},
+	{field: "port", protocols: []string{"http", "https", "tcp", "ssh", "smtp", "pop3"}},
+	{field: "qname", protocols: []string{"dns"}},
+	{field: "expected_data", protocols: []string{"dns", "snmp"}},
+	{field: "excepcted_data", protocols: []string{"dns", "snmp"}},
+	{field: "community", protocols: []string{"snmp"}},
+	{field: "snmp_version", protocols: []string{"snmp"}},
+	{field: "oid", protocols: []string{"snmp"}},
+}
+
+// validateSimpleMonitorConfig rejects health_check fields used with the wrong protocol.
+// It is called at apply time before any API call because Terraform can't validate across fields at plan time.
+// Missing fields are left to the API so that existing configurations keep working.
+func validateSimpleMonitorConfig(d *schema.ResourceData) error {
+	for _, c := range d.Get("health_check").([]interface{}) {
+		conf := c.(map[string]interface{})
+		protocol := conf["protocol"].(string)
+
+		for _, f := range simpleMonitorHealthCheckFields {
+			if isZeroValue(conf[f.field]) {
+				continue
+			}
+			allowed := false
+			for _, p := range f.protocols {
+				if p == protocol {
+					allowed = true
+					break
+				}
+			}
+			if !allowed {
+				return fmt.Errorf("health_check.%s can't be used with protocol %q, it is only for [%s]",
+					f.field, protocol, strings.Join(f.protocols, "/"))
+			}
+		}
 	}
+	return nil
+}
 
-	delay_loop := target["delay_loop"].(int)
+func isZeroValue(v interface{}) bool {
+	switch v := v.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case int:
+		return v == 0
+	}
+	return false
+}
 
-	hk := fmt.Sprintf("%s:%d:%s:%s:%s:%s:%s:%s:%s:%s", protocol, delay_loop, path, status, port, qname, ed, community, snmpVersion, oid)
-	return hashcode.String(hk)
+// simpleMonitorExpectedData returns expected_data, or excepcted_data(deprecated) if it isn't set
+func simpleMonitorExpectedData(conf map[string]interface{}) string {
+	if v := forceString(conf["expected_data"]); v != "" {
+		return v
+	}
+	return forceString(conf["excepcted_data"])
 }
 
 func setSimpleMonitorResourceData(d *schema.ResourceData, _ *api.Client, data *sacloud.SimpleMonitor) error {
@@ -450,9 +501,15 @@ func setSimpleMonitorResourceData(d *schema.ResourceData, _ *api.Client, data *s
 		healthCheck["expected_data"] = readHealthCheck.ExpectedData
 	}
 
+	// keep the deprecated field only while it is used in the config
+	if _, ok := d.GetOk("health_check.0.excepcted_data"); ok {
+		healthCheck["excepcted_data"] = healthCheck["expected_data"]
+		delete(healthCheck, "expected_data")
+	}
+
 	healthCheck["protocol"] = data.Settings.SimpleMonitor.HealthCheck.Protocol
 	healthCheck["delay_loop"] = data.Settings.SimpleMonitor.DelayLoop
-	d.Set("health_check", schema.NewSet(healthCheckSimpleMonitorHash, []interface{}{healthCheck}))
+	d.Set("health_check", []interface{}{healthCheck})
 
 	d.Set("description", data.Description)
 	d.Set("tags", data.Tags)
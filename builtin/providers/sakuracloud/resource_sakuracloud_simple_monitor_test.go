@@ -7,6 +7,7 @@ import (
 	"github.com/hashicorp/terraform/terraform"
 	"github.com/sacloud/libsacloud/api"
 	"github.com/sacloud/libsacloud/sacloud"
+	"regexp"
 	"testing"
 )
 
@@ -23,9 +24,9 @@ func TestAccResourceSakuraCloudSimpleMonitor(t *testing.T) {
 					testAccCheckSakuraCloudSimpleMonitorExists("sakuracloud_simple_monitor.foobar", &monitor),
 					testAccCheckSakuraCloudSimpleMonitorAttributes(&monitor),
 					resource.TestCheckResourceAttr(
-						"sakuracloud_simple_monitor.foobar", "health_check.109142589.protocol", "http"),
+						"sakuracloud_simple_monitor.foobar", "health_check.0.protocol", "http"),
 					resource.TestCheckResourceAttr(
-						"sakuracloud_simple_monitor.foobar", "health_check.109142589.delay_loop", "60"),
+						"sakuracloud_simple_monitor.foobar", "health_check.0.delay_loop", "60"),
 					resource.TestCheckResourceAttr(
 						"sakuracloud_simple_monitor.foobar", "target", "terraform.io"),
 					resource.TestCheckResourceAttr(
@@ -44,7 +45,7 @@ func TestAccResourceSakuraCloudSimpleMonitor(t *testing.T) {
 					testAccCheckSakuraCloudSimpleMonitorExists("sakuracloud_simple_monitor.foobar", &monitor),
 					testAccCheckSakuraCloudSimpleMonitorAttributesUpdated(&monitor),
 					resource.TestCheckResourceAttr(
-						"sakuracloud_simple_monitor.foobar", "health_check.1187190475.host_header", "libsacloud.com"),
+						"sakuracloud_simple_monitor.foobar", "health_check.0.host_header", "libsacloud.com"),
 					resource.TestCheckResourceAttr(
 						"sakuracloud_simple_monitor.foobar", "target", "terraform.io"),
 					resource.TestCheckResourceAttr(
@@ -55,6 +56,10 @@ func TestAccResourceSakuraCloudSimpleMonitor(t *testing.T) {
 						"sakuracloud_simple_monitor.foobar", "notify_email_enabled", "false"),
 				),
 			},
+			{
+				Config:      testAccCheckSakuraCloudSimpleMonitorConfig_invalidField,
+				ExpectError: regexp.MustCompile("health_check.qname can't be used with protocol \"http\""),
+			},
 		},
 	})
 }
@@ -160,4 +165,19 @@ resource "sakuracloud_simple_monitor" "foobar" {
     notify_slack_webhook = "%s"
 }`, testAccSlackWebhook)
 
+var testAccCheckSakuraCloudSimpleMonitorConfig_invalidField = fmt.Sprintf(`
+resource "sakuracloud_simple_monitor" "foobar" {
+    target = "terraform.io"
+    health_check = {
+        protocol = "http"
+        delay_loop = 120
+        path = "/"
+        status = "200"
+        qname = "terraform.io"
+    }
+    notify_email_enabled = false
+    notify_slack_enabled = true
+    notify_slack_webhook = "%s"
+}`, testAccSlackWebhook)
+
 const testAccSlackWebhook = `https://hooks.slack.com/services/XXXXXXXXX/XXXXXXXXX/XXXXXXXXXXXXXXXXXXXXXXXX`
@@ -19,10 +19,11 @@ func resourceSakuraCloudSubnet() *schema.Resource {
 
 		Schema: map[string]*schema.Schema{
 			"internet_id": {
-				Type:         schema.TypeString,
-				ForceNew:     true,
-				Required:     true,
-				ValidateFunc: validateSakuracloudIDType,
+				Type:             schema.TypeString,
+				ForceNew:         true,
+				Required:         true,
+				ValidateFunc:     validateSakuracloudIDType,
+				DiffSuppressFunc: suppressReplacedInternetID,
 			},
 			"nw_mask_len": {
 				Type:         schema.TypeInt,
@@ -32,8 +33,9 @@ func resourceSakuraCloudSubnet() *schema.Resource {
 				Default:      28,
 			},
 			"next_hop": {
-				Type:     schema.TypeString,
-				Required: true,
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validateIPv4Address,
 			},
 			"zone": {
 				Type:         schema.TypeString,
@@ -64,6 +66,16 @@ func resourceSakuraCloudSubnet() *schema.Resource {
 				Computed: true,
 				Elem:     &schema.Schema{Type: schema.TypeString},
 			},
+			"used_ipaddresses": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"free_ipaddresses": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
 		},
 	}
 }
@@ -81,6 +93,10 @@ func resourceSakuraCloudSubnetCreate(d *schema.ResourceData, meta interface{}) e
 	nwMaskLen := d.Get("nw_mask_len").(int)
 	nextHop := d.Get("next_hop").(string)
 
+	if err := validateSubnetNextHop(client, internetID, nextHop); err != nil {
+		return fmt.Errorf("Failed to create SakuraCloud Subnet resource: %s", err)
+	}
+
 	subnet, err := client.Internet.AddSubnet(internetID, nwMaskLen, nextHop)
 	if err != nil {
 		return fmt.Errorf("Failed to create SakuraCloud Subnet resource: %s", err)
@@ -115,11 +131,19 @@ func resourceSakuraCloudSubnetUpdate(d *schema.ResourceData, meta interface{}) e
 	}
 
 	if d.HasChange("next_hop") {
-		internetID := toSakuraCloudID(d.Get("internet_id").(string))
 		subnet, err := client.Subnet.Read(toSakuraCloudID(d.Id()))
 		if err != nil {
 			return fmt.Errorf("Couldn't find SakuraCloud Subnet resource: %s", err)
 		}
+		if subnet.Switch == nil || subnet.Switch.Internet == nil {
+			return fmt.Errorf("Error updating SakuraCloud Subnet resource: %s", "internet is nil")
+		}
+
+		// the router in the state may be replaced by changing its bandwidth in the same apply, so use the current one
+		internetID := subnet.Switch.Internet.ID
+		if err := validateSubnetNextHop(client, internetID, d.Get("next_hop").(string)); err != nil {
+			return fmt.Errorf("Error updating SakuraCloud Subnet resource: %s", err)
+		}
 
 		subnet, err = client.Internet.UpdateSubnet(internetID, subnet.ID, d.Get("next_hop").(string))
 		if err != nil {
@@ -140,7 +164,12 @@ func resourceSakuraCloudSubnetDelete(d *schema.ResourceData, meta interface{}) e
 		client.Zone = zone.(string)
 	}
 
+	// the router in the state may be replaced by changing its bandwidth, so use the current one
 	internetID := toSakuraCloudID(d.Get("internet_id").(string))
+	if subnet, err := client.Subnet.Read(toSakuraCloudID(d.Id())); err == nil && subnet.Switch != nil && subnet.Switch.Internet != nil {
+		internetID = subnet.Switch.Internet.ID
+	}
+
 	_, err := client.Internet.DeleteSubnet(internetID, toSakuraCloudID(d.Id()))
 	if err != nil {
 		return fmt.Errorf("Error deleting SakuraCloud Subnet resource: %s", err)
@@ -175,6 +204,71 @@ func setSubnetResourceData(d *schema.ResourceData, client *api.Client, data *sac
 	}
 	d.Set("ipaddresses", addrs)
 
+	used, err := subnetUsedIPAddresses(client, data.Switch.ID)
+	if err != nil {
+		return err
+	}
+	usedAddrs := []string{}
+	freeAddrs := []string{}
+	for _, ip := range addrs {
+		if used[ip] {
+			usedAddrs = append(usedAddrs, ip)
+		} else {
+			freeAddrs = append(freeAddrs, ip)
+		}
+	}
+	d.Set("used_ipaddresses", usedAddrs)
+	d.Set("free_ipaddresses", freeAddrs)
+
 	d.SetId(data.GetStrID())
 	return nil
 }
+
+// validateSubnetNextHop checks nextHop is in the address range of the router(sakuracloud_internet).
+// It needs the router, so it can't be done in ValidateFunc.
+func validateSubnetNextHop(client *api.Client, internetID int64, nextHop string) error {
+	internet, err := client.Internet.Read(internetID)
+	if err != nil {
+		return fmt.Errorf("Couldn't find SakuraCloud Internet resource: %s", err)
+	}
+	sw, err := client.Switch.Read(internet.Switch.ID)
+	if err != nil {
+		return fmt.Errorf("Couldn't find SakuraCloud Switch resource: %s", err)
+	}
+	ipList, err := sw.GetIPAddressList()
+	if err != nil {
+		return fmt.Errorf("Error reading Switch resource(IPAddresses): %s", err)
+	}
+
+	for _, ip := range ipList {
+		if ip == nextHop {
+			return nil
+		}
+	}
+	return fmt.Errorf("next_hop %q must be in the range of Internet(id:%d): %s - %s",
+		nextHop, internetID, sw.Subnets[0].IPAddresses.Min, sw.Subnets[0].IPAddresses.Max)
+}
+
+// subnetUsedIPAddresses returns the addresses assigned to NICs of the servers connected to the switch
+func subnetUsedIPAddresses(client *api.Client, switchID int64) (map[string]bool, error) {
+	servers, err := client.Switch.GetServers(switchID)
+	if err != nil {
+		return nil, fmt.Errorf("Couldn't find SakuraCloud Servers( is connected Switch): %s", err)
+	}
+
+	used := map[string]bool{}
+	for _, server := range servers {
+		for _, nic := range server.Interfaces {
+			if nic.Switch == nil || nic.Switch.ID != switchID {
+				continue
+			}
+			if nic.IPAddress != "" {
+				used[nic.IPAddress] = true
+			}
+			if nic.UserIPAddress != "" {
+				used[nic.UserIPAddress] = true
+			}
+		}
+	}
+	return used, nil
+}
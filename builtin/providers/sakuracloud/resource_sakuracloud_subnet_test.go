@@ -7,6 +7,7 @@ import (
 	"github.com/hashicorp/terraform/terraform"
 	"github.com/sacloud/libsacloud/api"
 	"github.com/sacloud/libsacloud/sacloud"
+	"regexp"
 	"testing"
 )
 
@@ -23,8 +24,16 @@ func TestAccResourceSakuraCloudSubnet(t *testing.T) {
 					testAccCheckSakuraCloudSubnetExists("sakuracloud_subnet.foobar", &subnet),
 					resource.TestCheckResourceAttr(
 						"sakuracloud_subnet.foobar", "ipaddresses.#", "16"),
+					resource.TestCheckResourceAttr(
+						"sakuracloud_subnet.foobar", "used_ipaddresses.#", "0"),
+					resource.TestCheckResourceAttr(
+						"sakuracloud_subnet.foobar", "free_ipaddresses.#", "16"),
 				),
 			},
+			{
+				Config:      testAccCheckSakuraCloudSubnetConfig_invalidNextHop,
+				ExpectError: regexp.MustCompile("next_hop \"192.0.2.1\" must be in the range of Internet"),
+			},
 			{
 				Config: testAccCheckSakuraCloudSubnetConfig_update,
 				Check: resource.ComposeTestCheckFunc(
@@ -33,6 +42,40 @@ func TestAccResourceSakuraCloudSubnet(t *testing.T) {
 						"sakuracloud_subnet.foobar", "ipaddresses.#", "16"),
 				),
 			},
+			{
+				// the router gets new ID by changing band_width, and next_hop is changed in the same apply
+				Config: testAccCheckSakuraCloudSubnetConfig_updateBandWidth,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckSakuraCloudSubnetExists("sakuracloud_subnet.foobar", &subnet),
+					resource.TestCheckResourceAttrPair(
+						"sakuracloud_subnet.foobar", "internet_id",
+						"sakuracloud_internet.foobar", "id"),
+					resource.TestCheckResourceAttrPair(
+						"sakuracloud_subnet.foobar", "next_hop",
+						"sakuracloud_internet.foobar", "nw_min_ipaddress"),
+				),
+			},
+			{
+				Config: testAccCheckSakuraCloudSubnetConfig_withServer,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair(
+						"sakuracloud_server.foobar", "network_interface.1.upstream",
+						"sakuracloud_subnet.foobar", "switch_id"),
+				),
+			},
+			{
+				// used_ipaddresses is updated at refresh after the server is connected
+				Config: testAccCheckSakuraCloudSubnetConfig_withServer,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(
+						"sakuracloud_subnet.foobar", "used_ipaddresses.#", "1"),
+					resource.TestCheckResourceAttrPair(
+						"sakuracloud_subnet.foobar", "used_ipaddresses.0",
+						"sakuracloud_subnet.foobar", "min_ipaddress"),
+					resource.TestCheckResourceAttr(
+						"sakuracloud_subnet.foobar", "free_ipaddresses.#", "15"),
+				),
+			},
 		},
 	})
 }
@@ -101,3 +144,42 @@ resource "sakuracloud_subnet" "foobar" {
     internet_id = "${sakuracloud_internet.foobar.id}"
     next_hop = "${sakuracloud_internet.foobar.nw_max_ipaddress}"
 }`
+
+var testAccCheckSakuraCloudSubnetConfig_updateBandWidth = `
+resource sakuracloud_internet "foobar" {
+    name = "myinternet"
+    band_width = 250
+}
+resource "sakuracloud_subnet" "foobar" {
+    internet_id = "${sakuracloud_internet.foobar.id}"
+    next_hop = "${sakuracloud_internet.foobar.nw_min_ipaddress}"
+}`
+
+var testAccCheckSakuraCloudSubnetConfig_invalidNextHop = `
+resource sakuracloud_internet "foobar" {
+    name = "myinternet"
+}
+resource "sakuracloud_subnet" "foobar" {
+    internet_id = "${sakuracloud_internet.foobar.id}"
+    next_hop = "192.0.2.1"
+}`
+
+var testAccCheckSakuraCloudSubnetConfig_withServer = `
+resource sakuracloud_internet "foobar" {
+    name = "myinternet"
+}
+resource "sakuracloud_subnet" "foobar" {
+    internet_id = "${sakuracloud_internet.foobar.id}"
+    next_hop = "${sakuracloud_internet.foobar.nw_max_ipaddress}"
+}
+resource "sakuracloud_server" "foobar" {
+    name = "myserver"
+    power_state = "down"
+    network_interface {
+        upstream = "shared"
+    }
+    network_interface {
+        upstream = "${sakuracloud_subnet.foobar.switch_id}"
+        user_ip_address = "${sakuracloud_subnet.foobar.min_ipaddress}"
+    }
+}`
@@ -241,7 +241,7 @@ func resourceSakuraCloudSwitchDelete(d *schema.ResourceData, meta interface{}) e
 		if err != nil {
 			return fmt.Errorf("Error booting SakuraCloud Server resource: %s", err)
 		}
-		err = client.Server.SleepUntilUp(id, client.DefaultTimeoutDuration)
+		err = sleepUntilServerUp(client, id)
 		if err != nil {
 			return fmt.Errorf("Error booting SakuraCloud Server resource: %s", err)
 		}
@@ -97,20 +97,17 @@ func flattenInterfaces(interfaces []sacloud.Interface) []interface{} {
 }
 
 func flattenPacketFilters(interfaces []sacloud.Interface) []string {
-	var ret []string
-	var isExists = false
-	for index, i := range interfaces {
+	ret := []string{}
+	for _, i := range interfaces {
 		var id string
 		if i.PacketFilter != nil {
 			id = i.PacketFilter.GetStrID()
-			isExists = true
-		}
-		if index == 0 || (len(interfaces)-1 == index && id != "") {
-			ret = append(ret, id)
 		}
+		ret = append(ret, id)
 	}
-	if !isExists {
-		return []string{}
+	// trailing NICs without packet filter are omitted
+	for len(ret) > 0 && ret[len(ret)-1] == "" {
+		ret = ret[:len(ret)-1]
 	}
 	return ret
 }
@@ -165,12 +162,19 @@ func expandFilters(filter interface{}) map[string]interface{} {
 type migrateSchemaDef struct {
 	source      string
 	destination string
+	// convert returns the value of destination built from source when the structure is changed.
+	// The value isn't saved to the state but returned by Get/GetOk of resourceData.
+	// If convert is nil, the value of source is copied to destination.
+	convert func(d *schema.ResourceData) (interface{}, bool)
 }
 
 func migrateResourceData(d *schema.ResourceData, _ interface{}, defs []migrateSchemaDef) resourceData {
 
 	// migrate deprecated params
 	for _, def := range defs {
+		if def.convert != nil {
+			continue
+		}
 		if v, ok := d.GetOk(def.source); ok {
 			d.Set(def.destination, v)
 		}
@@ -211,12 +215,38 @@ type resourceDataWrapper struct {
 func (d *resourceDataWrapper) HasChange(key string) bool {
 	origFunc := d.ResourceData.HasChange
 
+	changed := origFunc(key)
 	for _, def := range d.migrateDefs {
 		if def.source == key || def.destination == key {
-			return origFunc(def.source) || origFunc(def.destination)
+			changed = changed || origFunc(def.source) || origFunc(def.destination)
+		}
+	}
+	return changed
+}
+
+func (d *resourceDataWrapper) Get(key string) interface{} {
+	if v, ok := d.convertedValue(key); ok {
+		return v
+	}
+	return d.ResourceData.Get(key)
+}
+
+func (d *resourceDataWrapper) GetOk(key string) (interface{}, bool) {
+	if v, ok := d.convertedValue(key); ok {
+		return v, true
+	}
+	return d.ResourceData.GetOk(key)
+}
+
+func (d *resourceDataWrapper) convertedValue(key string) (interface{}, bool) {
+	for _, def := range d.migrateDefs {
+		if def.destination == key && def.convert != nil {
+			if v, ok := def.convert(d.ResourceData); ok {
+				return v, true
+			}
 		}
 	}
-	return origFunc(key)
+	return nil, false
 }
 
 func (d *resourceDataWrapper) RawResourceData() *schema.ResourceData {
@@ -3,6 +3,8 @@ package sakuracloud
 import (
 	"fmt"
 	"github.com/hashicorp/terraform/helper/schema"
+	"net"
+	"regexp"
 	"strconv"
 	"strings"
 	"unicode/utf8"
@@ -48,6 +50,17 @@ func validateIntegerInRange(min, max int) schema.SchemaValidateFunc {
 	}
 }
 
+func validateIntegerAtLeast(min int) schema.SchemaValidateFunc {
+	return func(v interface{}, k string) (ws []string, errors []error) {
+		value := v.(int)
+		if value < min {
+			errors = append(errors, fmt.Errorf(
+				"%q cannot be lower than %d: %d", k, min, value))
+		}
+		return
+	}
+}
+
 func validateSakuracloudIDType(v interface{}, k string) ([]string, []error) {
 	ws := []string{}
 	errors := []error{}
@@ -145,3 +158,154 @@ func validateBackupTime() schema.SchemaValidateFunc {
 
 	return validateStringInWord(timeStrings)
 }
+
+func validateServerNICUpstream(v interface{}, k string) ([]string, []error) {
+	value := v.(string)
+	if value == serverNICUpstreamShared || value == serverNICUpstreamDisconnect {
+		return nil, nil
+	}
+	if _, err := strconv.ParseInt(value, 10, 64); err != nil {
+		return nil, []error{fmt.Errorf("%q must be one of [%s/%s] or switch ID: %q", k, serverNICUpstreamShared, serverNICUpstreamDisconnect, value)}
+	}
+	return nil, nil
+}
+
+// validateNoteContent validates cloud-config content.
+// YAML parser isn't available, so only errors which cloud-init always rejects are checked.
+func validateNoteContent(v interface{}, k string) ([]string, []error) {
+	value := v.(string)
+	if !strings.HasPrefix(value, cloudConfigHeader) {
+		return nil, nil
+	}
+
+	var errors []error
+	lines := strings.Split(value, "\n")
+	for i, line := range lines[1:] {
+		lineNo := i + 2
+		line = strings.TrimRight(line, "\r")
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		if strings.HasPrefix(strings.TrimLeft(line, " "), "\t") {
+			errors = append(errors, fmt.Errorf("%q line %d: tabs can't be used for indentation in cloud-config", k, lineNo))
+			continue
+		}
+
+		// block sequence entries may start at column 0 under a key("packages:\n- vim")
+		if line == "-" || strings.HasPrefix(line, "- ") {
+			continue
+		}
+
+		// top level of cloud-config must be a mapping
+		if line == trimmed && line != "---" && line != "..." {
+			idx := strings.Index(line, ":")
+			if idx <= 0 || (idx < len(line)-1 && line[idx+1] != ' ') {
+				errors = append(errors, fmt.Errorf("%q line %d: top level of cloud-config must be \"key: value\" form: %q", k, lineNo, line))
+			}
+		}
+	}
+	return nil, errors
+}
+
+func validateBackupGeneration(v interface{}, k string) ([]string, []error) {
+	value := v.(string)
+	if value == "" || value == diskRestoreFromLatestBackup {
+		return nil, nil
+	}
+	if i, err := strconv.Atoi(value); err != nil || i < 0 {
+		return nil, []error{fmt.Errorf("%q must be %q or backup generation(0 or greater): %q", k, diskRestoreFromLatestBackup, value)}
+	}
+	return nil, nil
+}
+
+// validatePacketFilterSourceNetwork accepts an IP address, "address/prefix length" or "address/netmask"
+func validatePacketFilterSourceNetwork(v interface{}, k string) ([]string, []error) {
+	value := v.(string)
+	if value == "" {
+		return nil, nil
+	}
+
+	parts := strings.SplitN(value, "/", 2)
+	if ip := net.ParseIP(parts[0]); ip == nil || ip.To4() == nil {
+		return nil, []error{fmt.Errorf("%q must be an IPv4 address, CIDR or address/netmask: %q", k, value)}
+	}
+	if len(parts) == 2 {
+		if _, _, err := net.ParseCIDR(value); err == nil {
+			return nil, nil
+		}
+		mask := net.ParseIP(parts[1])
+		if mask == nil || mask.To4() == nil {
+			return nil, []error{fmt.Errorf("%q must be an IPv4 address, CIDR or address/netmask: %q", k, value)}
+		}
+		if ones, bits := net.IPMask(mask.To4()).Size(); ones == 0 && bits == 0 {
+			return nil, []error{fmt.Errorf("%q must have a contiguous netmask: %q", k, value)}
+		}
+	}
+	return nil, nil
+}
+
+// validatePacketFilterExpressionDescription rejects descriptions which look like the marker of sakuracloud_packet_filter_rules
+func validatePacketFilterExpressionDescription(v interface{}, k string) ([]string, []error) {
+	value := v.(string)
+	if packetFilterRulesDescriptionPattern.MatchString(value) {
+		return nil, []error{fmt.Errorf("%q can't start with \"[name:order]\", it is reserved for sakuracloud_packet_filter_rules: %q", k, value)}
+	}
+	return nil, nil
+}
+
+var packetFilterHexPortPattern = regexp.MustCompile(`^0x[0-9a-fA-F]{1,4}/0x[0-9a-fA-F]{1,4}$`)
+
+// validatePacketFilterPort accepts a port, "from-to" range or "0xPPPP/0xMMMM" masked range
+func validatePacketFilterPort(v interface{}, k string) ([]string, []error) {
+	value := v.(string)
+	if value == "" || packetFilterHexPortPattern.MatchString(value) {
+		return nil, nil
+	}
+
+	ports := strings.SplitN(value, "-", 2)
+	values := []int{}
+	for _, p := range ports {
+		port, err := strconv.Atoi(p)
+		if err != nil || port < 0 || port > 65535 {
+			return nil, []error{fmt.Errorf("%q must be a port(0-65535), range(xx-yy) or masked range(0xPPPP/0xMMMM): %q", k, value)}
+		}
+		values = append(values, port)
+	}
+	if len(values) == 2 && values[0] > values[1] {
+		return nil, []error{fmt.Errorf("%q must be ascending range: %q", k, value)}
+	}
+	return nil, nil
+}
+
+var packetFilterRulesNamePattern = regexp.MustCompile(`^[0-9A-Za-z_\-]{1,32}$`)
+
+func validatePacketFilterRulesName(v interface{}, k string) ([]string, []error) {
+	value := v.(string)
+	if !packetFilterRulesNamePattern.MatchString(value) {
+		return nil, []error{fmt.Errorf("%q must be 1-32 characters of alphanumerics, '_' or '-': %q", k, value)}
+	}
+	return nil, nil
+}
+
+func validatePacketFilterFlowPort(v interface{}, k string) ([]string, []error) {
+	value := v.(string)
+	if value == "" {
+		return nil, nil
+	}
+	if port, err := strconv.Atoi(value); err != nil || port < 0 || port > 65535 {
+		return nil, []error{fmt.Errorf("%q must be a port(0-65535): %q", k, value)}
+	}
+	return nil, nil
+}
+
+func validateIPv4Address(v interface{}, k string) ([]string, []error) {
+	value := v.(string)
+	if value == "" {
+		return nil, nil
+	}
+	if ip := net.ParseIP(value); ip == nil || ip.To4() == nil {
+		return nil, []error{fmt.Errorf("%q must be an IPv4 address: %q", k, value)}
+	}
+	return nil, nil
+}